@@ -1,7 +1,9 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // AddonSpec defines the desired state of Addon.
@@ -20,6 +22,341 @@ type AddonSpec struct {
 	// This field is immutable.
 	// TODO: enforce immutablity in webhook
 	Install AddonInstallSpec `json:"install"`
+
+	// Defines a planned maintenance window for this Addon.
+	// While the window is active, the Maintenance condition is reported
+	// and an Available Addon is kept Available while its workloads are unready,
+	// to suppress alerting. Configuration errors and termination are still reported.
+	// +optional
+	MaintenanceWindow *AddonMaintenanceWindow `json:"maintenanceWindow,omitempty"`
+
+	// Whether the Addon is reinstalled when its CSV is deleted out-of-band,
+	// defaults to Reinstall.
+	// +kubebuilder:validation:Enum={"Reinstall","Never"}
+	// +optional
+	OrphanedCSVPolicy OrphanedCSVPolicy `json:"orphanedCSVPolicy,omitempty"`
+
+	// Defines a ServiceAccount to create in the install namespace of the Addon,
+	// e.g. to pull images from private registries.
+	// +optional
+	ServiceAccount *AddonServiceAccount `json:"serviceAccount,omitempty"`
+
+	// Defines a LimitRange to create in the install namespace of the Addon,
+	// e.g. to set default resource requests and limits for Pods.
+	// +optional
+	LimitRange *AddonLimitRange `json:"limitRange,omitempty"`
+
+	// Defines SecurityContextConstraints to grant to the ServiceAccounts
+	// in the install namespace of the Addon.
+	// +optional
+	SCC *AddonSCC `json:"scc,omitempty"`
+
+	// Names of Deployments in the install namespace of the Addon,
+	// that have to be fully available before the Addon is reported as Available.
+	// +optional
+	RequiredDeployments []string `json:"requiredDeployments,omitempty"`
+
+	// Restricts egress traffic out of the install namespace of the Addon.
+	// Only available on OpenShift clusters.
+	// +optional
+	Egress *AddonEgress `json:"egress,omitempty"`
+
+	// Defines CronJobs running periodic tasks in the install namespace of the Addon,
+	// e.g. certificate rotation checks or cleanups.
+	// +optional
+	CronJobs []AddonCronJob `json:"cronJobs,omitempty"`
+
+	// Names of cluster feature gates that have to be enabled,
+	// before the Addon is installed.
+	// Feature sets, like TechPreviewNoUpgrade, can be required by their name.
+	// Only available on OpenShift clusters.
+	// +optional
+	RequiredFeatureGates []string `json:"requiredFeatureGates,omitempty"`
+
+	// Defines a PriorityClass the Pods of the Addon are referencing,
+	// e.g. to guarantee scheduling of critical components.
+	// +optional
+	PriorityClass *AddonPriorityClass `json:"priorityClass,omitempty"`
+
+	// Replaces container image registries in the Deployments of the Addon,
+	// e.g. to pull images from a mirror registry in disconnected clusters.
+	// +optional
+	RegistryOverrides []AddonRegistryOverride `json:"registryOverrides,omitempty"`
+
+	// Defines a HorizontalPodAutoscaler scaling a Deployment of the Addon
+	// based on its CPU utilization.
+	// +optional
+	HPA *AddonHPA `json:"hpa,omitempty"`
+
+	// Defines a VerticalPodAutoscaler sizing the resource requests
+	// of a Deployment of the Addon.
+	// Requires the VerticalPodAutoscaler operator to be installed on the cluster.
+	// +optional
+	VPA *AddonVPA `json:"vpa,omitempty"`
+
+	// Defines a PodDisruptionBudget protecting the availability
+	// of the workloads of the Addon during voluntary disruptions.
+	// +optional
+	PDB *AddonPDB `json:"pdb,omitempty"`
+
+	// Watches TLS certificates in Secrets of the Addon
+	// and warns before they expire.
+	// +optional
+	CertificateExpiry *AddonCertificateExpiry `json:"certificateExpiry,omitempty"`
+
+	// PodSecurity admission level required by the Addon,
+	// enforced on all Namespaces of the Addon.
+	// +kubebuilder:validation:Enum={"privileged","baseline","restricted"}
+	// +optional
+	PodSecurity PodSecurityLevel `json:"podSecurity,omitempty"`
+
+	// Enables platform monitoring of all Namespaces of the Addon,
+	// by keeping the cluster-monitoring label on them.
+	// The label is removed from Namespaces of the Addon again, when disabled.
+	// +optional
+	ClusterMonitoring bool `json:"clusterMonitoring,omitempty"`
+
+	// Defines ConfigMaps to create in the install namespace of the Addon,
+	// e.g. to seed configuration of the Addon operator.
+	// +optional
+	ConfigMaps []AddonConfigMap `json:"configMaps,omitempty"`
+
+	// Binds existing ClusterRoles to ServiceAccounts in the install namespace of the Addon,
+	// e.g. for operators that need cluster-scoped permissions.
+	// +optional
+	ClusterRoleBindings []AddonClusterRoleBinding `json:"clusterRoleBindings,omitempty"`
+
+	// Defines Secrets with generated values to create in the install namespace of the Addon,
+	// e.g. for random tokens. Secrets are generated once and never overwritten.
+	// +optional
+	GeneratedSecrets []AddonGeneratedSecret `json:"generatedSecrets,omitempty"`
+
+	// Defines Routes to create in the install namespace of the Addon,
+	// e.g. to expose a UI of the Addon.
+	// +optional
+	Routes []AddonRoute `json:"routes,omitempty"`
+}
+
+// AddonMaintenanceWindow defines a planned maintenance period of an Addon.
+type AddonMaintenanceWindow struct {
+	// Start of the maintenance window.
+	Start metav1.Time `json:"start"`
+	// End of the maintenance window.
+	End metav1.Time `json:"end"`
+}
+
+// AddonServiceAccount defines a ServiceAccount managed for an Addon.
+type AddonServiceAccount struct {
+	// Name of the ServiceAccount.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Names of Secrets in the install namespace of the Addon,
+	// that are used to pull images with this ServiceAccount.
+	// +optional
+	ImagePullSecrets []string `json:"imagePullSecrets,omitempty"`
+}
+
+// AddonLimitRange defines a LimitRange managed for an Addon.
+type AddonLimitRange struct {
+	// Limits to enforce in the install namespace of the Addon.
+	// +kubebuilder:validation:MinItems=1
+	Limits []corev1.LimitRangeItem `json:"limits"`
+}
+
+// AddonSCC defines SecurityContextConstraints granted to an Addon.
+type AddonSCC struct {
+	// Names of the SecurityContextConstraints to grant.
+	// The operator needs permission to use each of them itself,
+	// it is granted use of anyuid, nonroot and hostnetwork by default.
+	// +kubebuilder:validation:MinItems=1
+	Names []string `json:"names"`
+}
+
+// AddonEgress defines the egress traffic allowed for an Addon.
+// All egress traffic to destinations not listed here is denied.
+type AddonEgress struct {
+	// IPv4 CIDRs of destinations egress traffic is allowed to, e.g. 10.0.0.0/16.
+	// +kubebuilder:validation:MinItems=1
+	AllowedCIDRs []string `json:"allowedCIDRs"`
+}
+
+// AddonCronJob defines a CronJob managed for an Addon.
+type AddonCronJob struct {
+	// Name of the CronJob.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Schedule in Cron format, e.g. "0 */6 * * *".
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// Container image to run.
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+
+	// Command to run, defaults to the entrypoint of the image.
+	// +optional
+	Command []string `json:"command,omitempty"`
+}
+
+// AddonPriorityClass defines a PriorityClass referenced by an Addon.
+type AddonPriorityClass struct {
+	// Name of the PriorityClass.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Priority value of the PriorityClass.
+	// When set, the PriorityClass is created and managed for the Addon,
+	// otherwise it has to exist on the cluster already.
+	// +optional
+	Value *int32 `json:"value,omitempty"`
+}
+
+// AddonRegistryOverride maps a container image registry to a mirror.
+type AddonRegistryOverride struct {
+	// Registry or repository prefix to replace, e.g. quay.io or quay.io/osd-addons.
+	// +kubebuilder:validation:MinLength=1
+	Source string `json:"source"`
+
+	// Registry or repository prefix to use instead, e.g. mirror.example.com:5000.
+	// +kubebuilder:validation:MinLength=1
+	Mirror string `json:"mirror"`
+}
+
+// AddonHPA defines a HorizontalPodAutoscaler managed for an Addon.
+type AddonHPA struct {
+	// Name of the Deployment in the install namespace of the Addon to scale.
+	// +kubebuilder:validation:MinLength=1
+	DeploymentName string `json:"deploymentName"`
+
+	// Lower limit for the number of replicas, defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// Upper limit for the number of replicas.
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// Target average CPU utilization of all Pods,
+	// in percent of the requested CPU.
+	// +kubebuilder:validation:Minimum=1
+	TargetCPUUtilizationPercentage int32 `json:"targetCPUUtilizationPercentage"`
+}
+
+// AddonVPA defines a VerticalPodAutoscaler managed for an Addon.
+type AddonVPA struct {
+	// Name of the Deployment in the install namespace of the Addon to size.
+	// +kubebuilder:validation:MinLength=1
+	DeploymentName string `json:"deploymentName"`
+
+	// Whether the VerticalPodAutoscaler only recommends resource requests
+	// or also applies them to the Pods of the Deployment.
+	// +kubebuilder:validation:Enum={"Off","Auto"}
+	UpdateMode VPAUpdateMode `json:"updateMode"`
+}
+
+// AddonPDB defines a PodDisruptionBudget managed for an Addon.
+// Exactly one of MinAvailable and MaxUnavailable has to be set.
+type AddonPDB struct {
+	// Selects the Pods in the install namespace of the Addon
+	// protected by the PodDisruptionBudget.
+	Selector metav1.LabelSelector `json:"selector"`
+
+	// Number or percentage of selected Pods that must remain available.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// Number or percentage of selected Pods that may be unavailable.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// AddonCertificateExpiry defines the TLS certificates of an Addon
+// that are watched for their expiry.
+type AddonCertificateExpiry struct {
+	// Names of Secrets in the install namespace of the Addon,
+	// holding PEM encoded certificates in their tls.crt key.
+	// +kubebuilder:validation:MinItems=1
+	SecretNames []string `json:"secretNames"`
+
+	// Time before the expiry of a certificate
+	// from which the CertificateExpiringSoon condition is set.
+	// Defaults to 720h (30 days).
+	// +optional
+	WarningThreshold *metav1.Duration `json:"warningThreshold,omitempty"`
+}
+
+// AddonConfigMap defines a ConfigMap managed for an Addon.
+type AddonConfigMap struct {
+	// Name of the ConfigMap.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Data of the ConfigMap.
+	// Only the keys listed here are managed, other keys are left untouched.
+	// +optional
+	Data map[string]string `json:"data,omitempty"`
+}
+
+// AddonClusterRoleBinding binds an existing ClusterRole to a ServiceAccount of an Addon.
+type AddonClusterRoleBinding struct {
+	// Name of the ClusterRole to bind.
+	// The operator needs permission to bind it itself.
+	// +kubebuilder:validation:MinLength=1
+	ClusterRoleName string `json:"clusterRoleName"`
+
+	// Name of the ServiceAccount in the install namespace of the Addon.
+	// +kubebuilder:validation:MinLength=1
+	ServiceAccountName string `json:"serviceAccountName"`
+}
+
+// AddonGeneratedSecret defines a Secret with generated values managed for an Addon.
+type AddonGeneratedSecret struct {
+	// Name of the Secret.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Keys of the Secret to generate random values for.
+	// +kubebuilder:validation:MinItems=1
+	Keys []string `json:"keys"`
+}
+
+// AddonRoute defines an OpenShift Route managed for an Addon.
+type AddonRoute struct {
+	// Name of the Route.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Host the Route is exposed on,
+	// generated by the router when not set.
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// Name of the Service in the install namespace of the Addon to route traffic to.
+	// +kubebuilder:validation:MinLength=1
+	ServiceName string `json:"serviceName"`
+
+	// Name or number of the Service port to route traffic to.
+	// +optional
+	TargetPort *intstr.IntOrString `json:"targetPort,omitempty"`
+
+	// TLS configuration of the Route, the Route is served over plain HTTP when not set.
+	// +optional
+	TLS *AddonRouteTLS `json:"tls,omitempty"`
+}
+
+// AddonRouteTLS defines the TLS configuration of a Route of an Addon.
+type AddonRouteTLS struct {
+	// Where TLS is terminated.
+	// +kubebuilder:validation:Enum={"edge","passthrough","reencrypt"}
+	Termination string `json:"termination"`
+
+	// How insecure HTTP traffic is handled, defaults to None.
+	// +kubebuilder:validation:Enum={"None","Allow","Redirect"}
+	// +optional
+	InsecureEdgeTerminationPolicy string `json:"insecureEdgeTerminationPolicy,omitempty"`
 }
 
 // AddonInstallSpec defines the desired Addon installation type.
@@ -78,15 +415,100 @@ const (
 	OLMOwnNamespace AddonInstallType = "OLMOwnNamespace"
 )
 
+// PodSecurityLevel is a Pod Security Standards level.
+type PodSecurityLevel string
+
+const (
+	// Unrestricted policy, allowing known privilege escalations.
+	PodSecurityLevelPrivileged PodSecurityLevel = "privileged"
+	// Minimally restrictive policy, preventing known privilege escalations.
+	PodSecurityLevelBaseline PodSecurityLevel = "baseline"
+	// Heavily restricted policy, following Pod hardening best practices.
+	PodSecurityLevelRestricted PodSecurityLevel = "restricted"
+)
+
+// VPAUpdateMode controls how a VerticalPodAutoscaler applies its recommendations.
+type VPAUpdateMode string
+
+const (
+	// Only recommend resource requests, without changing Pods.
+	VPAUpdateModeOff VPAUpdateMode = "Off"
+	// Apply recommended resource requests to Pods, recreating them if needed.
+	VPAUpdateModeAuto VPAUpdateMode = "Auto"
+)
+
 type AddonNamespace struct {
 	// Name of the KubernetesNamespace.
 	// +kubebuilder:validation:MinLength=1
 	Name string `json:"name"`
+
+	// Whether the Namespace is recreated when it is deleted externally,
+	// defaults to Recreate.
+	// +kubebuilder:validation:Enum={"Recreate","Never"}
+	// +optional
+	RecreationPolicy NamespaceRecreationPolicy `json:"recreationPolicy,omitempty"`
 }
 
+// NamespaceRecreationPolicy controls how externally deleted Namespaces of an Addon are handled.
+type NamespaceRecreationPolicy string
+
+const (
+	// Recreate the Namespace and everything within it (default).
+	NamespaceRecreationPolicyRecreate NamespaceRecreationPolicy = "Recreate"
+	// Leave the Namespace deleted and only report it as missing.
+	NamespaceRecreationPolicyNever NamespaceRecreationPolicy = "Never"
+)
+
+// OrphanedCSVPolicy controls how Addons are handled, when their CSV is deleted externally.
+type OrphanedCSVPolicy string
+
+const (
+	// Delete the Subscription of the Addon, so OLM installs the CSV again (default).
+	OrphanedCSVPolicyReinstall OrphanedCSVPolicy = "Reinstall"
+	// Leave the CSV deleted and only report the Addon as orphaned.
+	OrphanedCSVPolicyNever OrphanedCSVPolicy = "Never"
+)
+
 const (
 	// Available condition indicates that all resources for the Addon are reconciled and healthy
 	Available = "Available"
+	// Maintenance condition indicates that the Addon is in a planned maintenance window,
+	// alerting based on other conditions should be suppressed while it is True
+	Maintenance = "Maintenance"
+	// UpgradeAvailable condition indicates that a newer version of the Addon
+	// is available in its channel, but not yet installed
+	UpgradeAvailable = "UpgradeAvailable"
+	// ExcessiveCopiedCSVs condition indicates that OLM created more copies of the CSV
+	// of an AllNamespaces Addon than expected, putting load on the cluster,
+	// or copies that are stale or out of sync with the CSV
+	ExcessiveCopiedCSVs = "ExcessiveCopiedCSVs"
+	// Deprecated condition indicates that the package, channel or bundle
+	// of the Addon is marked as deprecated in its catalog
+	Deprecated = "Deprecated"
+	// Orphaned condition indicates that the CSV of the Addon was deleted
+	// while the Addon still exists, a reinstall is triggered depending on the OrphanedCSVPolicy
+	Orphaned = "Orphaned"
+	// CertificateExpiringSoon condition indicates that a watched TLS certificate
+	// of the Addon expires within the configured warning threshold
+	CertificateExpiringSoon = "CertificateExpiringSoon"
+	// NamespaceMissing condition indicates that Namespaces of the Addon
+	// were deleted externally after they had been created
+	NamespaceMissing = "NamespaceMissing"
+)
+
+const (
+	// Deployments required by the Addon are not fully available yet
+	AddonReasonWorkloadsNotReady = "WorkloadsNotReady"
+	// Cluster requirements of the Addon are not met, installation is blocked
+	AddonReasonRequirementsNotMet = "RequirementsNotMet"
+	// The CatalogSource image of the Addon can not be pulled
+	AddonReasonCatalogImageError = "CatalogImageError"
+	// The CSV of the Addon is being replaced by a newer version
+	AddonReasonCSVReplacing = "CSVReplacing"
+	// The CSV of the Addon is waiting for its requirements to be met
+	AddonReasonCSVPending = "CSVPending"
+	// Reconciliation of the Addon did not finish within its deadline
+	AddonReasonReconcileTimeout = "ReconcileTimeout"
 )
 
 // AddonStatus defines the observed state of Addon
@@ -95,12 +517,28 @@ type AddonStatus struct {
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 	// Conditions is a list of status conditions ths object is in.
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Namespaces of the Addon that have been observed to exist,
+	// used to detect Namespaces deleted externally.
+	// +optional
+	ObservedNamespaces []string `json:"observedNamespaces,omitempty"`
+	// Last time monitoring labels were found missing
+	// on Namespaces of the Addon and had to be re-applied.
+	// +optional
+	MonitoringLabelsDrift *MonitoringLabelsDrift `json:"monitoringLabelsDrift,omitempty"`
 	// DEPRECATED: This field is not part of any API contract
 	// it will go away as soon as kubectl can print conditions!
 	// Human readable status - please use .Conditions from code
 	Phase AddonPhase `json:"phase,omitempty"`
 }
 
+// MonitoringLabelsDrift records monitoring labels re-applied to Namespaces of an Addon.
+type MonitoringLabelsDrift struct {
+	// Time the missing monitoring labels were observed and re-applied.
+	ObservedTime metav1.Time `json:"observedTime"`
+	// Namespaces the monitoring labels were re-applied on.
+	Namespaces []string `json:"namespaces"`
+}
+
 type AddonPhase string
 
 // Well-known Addon Phases for printing a Status in kubectl,
@@ -5,8 +5,10 @@
 package v1alpha1
 
 import (
-	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -36,6 +38,148 @@ func (in *Addon) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonCertificateExpiry) DeepCopyInto(out *AddonCertificateExpiry) {
+	*out = *in
+	if in.SecretNames != nil {
+		in, out := &in.SecretNames, &out.SecretNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.WarningThreshold != nil {
+		in, out := &in.WarningThreshold, &out.WarningThreshold
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonCertificateExpiry.
+func (in *AddonCertificateExpiry) DeepCopy() *AddonCertificateExpiry {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonCertificateExpiry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonClusterRoleBinding) DeepCopyInto(out *AddonClusterRoleBinding) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonClusterRoleBinding.
+func (in *AddonClusterRoleBinding) DeepCopy() *AddonClusterRoleBinding {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonClusterRoleBinding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonConfigMap) DeepCopyInto(out *AddonConfigMap) {
+	*out = *in
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonConfigMap.
+func (in *AddonConfigMap) DeepCopy() *AddonConfigMap {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonConfigMap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonCronJob) DeepCopyInto(out *AddonCronJob) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonCronJob.
+func (in *AddonCronJob) DeepCopy() *AddonCronJob {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonCronJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonEgress) DeepCopyInto(out *AddonEgress) {
+	*out = *in
+	if in.AllowedCIDRs != nil {
+		in, out := &in.AllowedCIDRs, &out.AllowedCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonEgress.
+func (in *AddonEgress) DeepCopy() *AddonEgress {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonEgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonGeneratedSecret) DeepCopyInto(out *AddonGeneratedSecret) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonGeneratedSecret.
+func (in *AddonGeneratedSecret) DeepCopy() *AddonGeneratedSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonGeneratedSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonHPA) DeepCopyInto(out *AddonHPA) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonHPA.
+func (in *AddonHPA) DeepCopy() *AddonHPA {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonHPA)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AddonInstallOLMAllNamespaces) DeepCopyInto(out *AddonInstallOLMAllNamespaces) {
 	*out = *in
@@ -108,6 +252,28 @@ func (in *AddonInstallSpec) DeepCopy() *AddonInstallSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonLimitRange) DeepCopyInto(out *AddonLimitRange) {
+	*out = *in
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = make([]v1.LimitRangeItem, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonLimitRange.
+func (in *AddonLimitRange) DeepCopy() *AddonLimitRange {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonLimitRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AddonList) DeepCopyInto(out *AddonList) {
 	*out = *in
@@ -140,6 +306,23 @@ func (in *AddonList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonMaintenanceWindow) DeepCopyInto(out *AddonMaintenanceWindow) {
+	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+	in.End.DeepCopyInto(&out.End)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonMaintenanceWindow.
+func (in *AddonMaintenanceWindow) DeepCopy() *AddonMaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonMaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AddonNamespace) DeepCopyInto(out *AddonNamespace) {
 	*out = *in
@@ -155,6 +338,147 @@ func (in *AddonNamespace) DeepCopy() *AddonNamespace {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonPDB) DeepCopyInto(out *AddonPDB) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonPDB.
+func (in *AddonPDB) DeepCopy() *AddonPDB {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonPDB)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonPriorityClass) DeepCopyInto(out *AddonPriorityClass) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonPriorityClass.
+func (in *AddonPriorityClass) DeepCopy() *AddonPriorityClass {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonPriorityClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonRegistryOverride) DeepCopyInto(out *AddonRegistryOverride) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonRegistryOverride.
+func (in *AddonRegistryOverride) DeepCopy() *AddonRegistryOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonRegistryOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonRoute) DeepCopyInto(out *AddonRoute) {
+	*out = *in
+	if in.TargetPort != nil {
+		in, out := &in.TargetPort, &out.TargetPort
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(AddonRouteTLS)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonRoute.
+func (in *AddonRoute) DeepCopy() *AddonRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonRouteTLS) DeepCopyInto(out *AddonRouteTLS) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonRouteTLS.
+func (in *AddonRouteTLS) DeepCopy() *AddonRouteTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonRouteTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonSCC) DeepCopyInto(out *AddonSCC) {
+	*out = *in
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonSCC.
+func (in *AddonSCC) DeepCopy() *AddonSCC {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonSCC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonServiceAccount) DeepCopyInto(out *AddonServiceAccount) {
+	*out = *in
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonServiceAccount.
+func (in *AddonServiceAccount) DeepCopy() *AddonServiceAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonServiceAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AddonSpec) DeepCopyInto(out *AddonSpec) {
 	*out = *in
@@ -164,6 +488,104 @@ func (in *AddonSpec) DeepCopyInto(out *AddonSpec) {
 		copy(*out, *in)
 	}
 	in.Install.DeepCopyInto(&out.Install)
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(AddonMaintenanceWindow)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceAccount != nil {
+		in, out := &in.ServiceAccount, &out.ServiceAccount
+		*out = new(AddonServiceAccount)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LimitRange != nil {
+		in, out := &in.LimitRange, &out.LimitRange
+		*out = new(AddonLimitRange)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SCC != nil {
+		in, out := &in.SCC, &out.SCC
+		*out = new(AddonSCC)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RequiredDeployments != nil {
+		in, out := &in.RequiredDeployments, &out.RequiredDeployments
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Egress != nil {
+		in, out := &in.Egress, &out.Egress
+		*out = new(AddonEgress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CronJobs != nil {
+		in, out := &in.CronJobs, &out.CronJobs
+		*out = make([]AddonCronJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RequiredFeatureGates != nil {
+		in, out := &in.RequiredFeatureGates, &out.RequiredFeatureGates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PriorityClass != nil {
+		in, out := &in.PriorityClass, &out.PriorityClass
+		*out = new(AddonPriorityClass)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RegistryOverrides != nil {
+		in, out := &in.RegistryOverrides, &out.RegistryOverrides
+		*out = make([]AddonRegistryOverride, len(*in))
+		copy(*out, *in)
+	}
+	if in.HPA != nil {
+		in, out := &in.HPA, &out.HPA
+		*out = new(AddonHPA)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VPA != nil {
+		in, out := &in.VPA, &out.VPA
+		*out = new(AddonVPA)
+		**out = **in
+	}
+	if in.PDB != nil {
+		in, out := &in.PDB, &out.PDB
+		*out = new(AddonPDB)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CertificateExpiry != nil {
+		in, out := &in.CertificateExpiry, &out.CertificateExpiry
+		*out = new(AddonCertificateExpiry)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigMaps != nil {
+		in, out := &in.ConfigMaps, &out.ConfigMaps
+		*out = make([]AddonConfigMap, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ClusterRoleBindings != nil {
+		in, out := &in.ClusterRoleBindings, &out.ClusterRoleBindings
+		*out = make([]AddonClusterRoleBinding, len(*in))
+		copy(*out, *in)
+	}
+	if in.GeneratedSecrets != nil {
+		in, out := &in.GeneratedSecrets, &out.GeneratedSecrets
+		*out = make([]AddonGeneratedSecret, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]AddonRoute, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonSpec.
@@ -181,11 +603,21 @@ func (in *AddonStatus) DeepCopyInto(out *AddonStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ObservedNamespaces != nil {
+		in, out := &in.ObservedNamespaces, &out.ObservedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MonitoringLabelsDrift != nil {
+		in, out := &in.MonitoringLabelsDrift, &out.MonitoringLabelsDrift
+		*out = new(MonitoringLabelsDrift)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonStatus.
@@ -197,3 +629,39 @@ func (in *AddonStatus) DeepCopy() *AddonStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonVPA) DeepCopyInto(out *AddonVPA) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonVPA.
+func (in *AddonVPA) DeepCopy() *AddonVPA {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonVPA)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringLabelsDrift) DeepCopyInto(out *MonitoringLabelsDrift) {
+	*out = *in
+	in.ObservedTime.DeepCopyInto(&out.ObservedTime)
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringLabelsDrift.
+func (in *MonitoringLabelsDrift) DeepCopy() *MonitoringLabelsDrift {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringLabelsDrift)
+	in.DeepCopyInto(out)
+	return out
+}
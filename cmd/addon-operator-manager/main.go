@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/pprof"
 	"os"
+	"time"
 
 	operatorsv1 "github.com/operator-framework/api/pkg/operators/v1"
 	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
@@ -38,6 +39,9 @@ func main() {
 		pprofAddr            string
 		enableLeaderElection bool
 		probeAddr            string
+		cacheFinalizer       string
+		reconcileTimeout     time.Duration
+		copiedCSVThreshold   int
 	)
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&pprofAddr, "pprof-addr", "", "The address the pprof web endpoint binds to.")
@@ -46,6 +50,12 @@ func main() {
 			"Enabling this will ensure there is only one active controller manager.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081",
 		"The address the probe endpoint binds to.")
+	flag.StringVar(&cacheFinalizer, "cache-finalizer", controllers.DefaultCacheFinalizer,
+		"Name of the finalizer put on Addons to clean up caches.")
+	flag.DurationVar(&reconcileTimeout, "reconcile-timeout", controllers.DefaultReconcileTimeout,
+		"Maximum duration of a single Addon reconciliation.")
+	flag.IntVar(&copiedCSVThreshold, "copied-csv-threshold", controllers.DefaultCopiedCSVThreshold,
+		"Number of copied CSVs of an AllNamespaces Addon, above which they are reported as excessive.")
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
@@ -111,9 +121,13 @@ func main() {
 	}
 
 	if err = (&controllers.AddonReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Addon"),
-		Scheme: mgr.GetScheme(),
+		Client:             mgr.GetClient(),
+		APIReader:          mgr.GetAPIReader(),
+		Log:                ctrl.Log.WithName("controllers").WithName("Addon"),
+		Scheme:             mgr.GetScheme(),
+		CacheFinalizer:     cacheFinalizer,
+		ReconcileTimeout:   reconcileTimeout,
+		CopiedCSVThreshold: copiedCSVThreshold,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Addon")
 		os.Exit(1)
@@ -1,8 +1,63 @@
 package main
 
-import "log"
+import (
+	"flag"
+	"os"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	aoapis "github.com/openshift/addon-operator/apis"
+	"github.com/openshift/addon-operator/internal/webhooks"
+)
+
+var (
+	scheme   = runtime.NewScheme()
+	setupLog = ctrl.Log.WithName("setup")
+)
+
+func init() {
+	_ = aoapis.AddToScheme(scheme)
+}
 
 func main() {
-	// Placeholder for the actual webhook stuff
-	log.Println("running webhook...")
+	var (
+		port    int
+		certDir string
+	)
+	flag.IntVar(&port, "port", 9443, "The port the webhook server binds to.")
+	flag.StringVar(&certDir, "cert-dir", "/tmp/k8s-webhook-server/serving-certs",
+		"The directory containing the serving certificate tls.crt and key tls.key.")
+	flag.Parse()
+
+	ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
+
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		setupLog.Error(err, "unable to create admission decoder")
+		os.Exit(1)
+	}
+
+	addonHandler := &webhooks.AddonWebhookHandler{}
+	if err := addonHandler.InjectDecoder(decoder); err != nil {
+		setupLog.Error(err, "unable to inject admission decoder")
+		os.Exit(1)
+	}
+
+	server := &webhook.Server{
+		Port:    port,
+		CertDir: certDir,
+	}
+	server.Register(webhooks.AddonValidationPath, &webhook.Admission{
+		Handler: addonHandler,
+	})
+
+	setupLog.Info("starting webhook server")
+	if err := server.Start(ctrl.SetupSignalHandler()); err != nil {
+		setupLog.Error(err, "problem running webhook server")
+		os.Exit(1)
+	}
 }
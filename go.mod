@@ -3,8 +3,10 @@ module github.com/openshift/addon-operator
 go 1.16
 
 require (
+	github.com/blang/semver/v4 v4.0.0
 	github.com/go-logr/logr v0.4.0
 	github.com/operator-framework/api v0.8.1
+	github.com/robfig/cron/v3 v3.0.1
 	github.com/stretchr/testify v1.6.1
 	k8s.io/api v0.20.2
 	k8s.io/apiextensions-apiserver v0.20.1
@@ -8,7 +8,12 @@ import (
 	"github.com/go-logr/logr"
 	operatorsv1 "github.com/operator-framework/api/pkg/operators/v1"
 	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -21,15 +26,33 @@ import (
 )
 
 // Default timeout when we do a manual RequeueAfter
-const (
-	defaultRetryAfterTime = 10 * time.Second
-	cacheFinalizer        = "addons.managed.openshift.io/cache"
-)
+const defaultRetryAfterTime = 10 * time.Second
+
+// Default name of the finalizer used to clean up caches when an Addon is deleted
+const DefaultCacheFinalizer = "addons.managed.openshift.io/cache"
+
+// Default maximum duration of a single Addon reconciliation
+const DefaultReconcileTimeout = 2 * time.Minute
+
+// Default number of copied CSVs of an AllNamespaces Addon, above which they are reported
+const DefaultCopiedCSVThreshold = 500
 
 type AddonReconciler struct {
 	client.Client
 	Log    logr.Logger
 	Scheme *runtime.Scheme
+	// Name of the finalizer used to clean up caches, defaults to DefaultCacheFinalizer.
+	// Allows multiple operator variants to manage overlapping Addons in test clusters.
+	CacheFinalizer string
+	// Maximum duration of a single Addon reconciliation, defaults to DefaultReconcileTimeout.
+	// Prevents a single stuck Addon from blocking a worker indefinitely.
+	ReconcileTimeout time.Duration
+	// Number of copied CSVs of an AllNamespaces Addon, above which the
+	// ExcessiveCopiedCSVs condition is reported, defaults to DefaultCopiedCSVThreshold.
+	CopiedCSVThreshold int
+	// Reads objects directly from the API server, defaults to the Client.
+	// Used for objects the operator doesn't watch, so they are not cached cluster-wide.
+	APIReader client.Reader
 
 	csvEventHandler csvEventHandler
 }
@@ -43,16 +66,51 @@ type csvEventHandler interface {
 func (r *AddonReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.csvEventHandler = internalhandler.NewCSVEventHandler()
 
-	return ctrl.NewControllerManagedBy(mgr).
+	// ConfigMaps and Routes are watched by label, instead of caching all of them in the cluster.
+	// Secrets are not watched at all, generated Secrets are never reconciled once created.
+	configMapInformer, err := newCommonLabelInformer(mgr, corev1.SchemeGroupVersion.WithResource("configmaps"))
+	if err != nil {
+		return err
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&addonsv1alpha1.Addon{}).
 		Owns(&corev1.Namespace{}).
+		Owns(&corev1.ServiceAccount{}).
+		Owns(&corev1.LimitRange{}).
+		Owns(&rbacv1.RoleBinding{}).
+		Owns(&rbacv1.ClusterRoleBinding{}).
+		Owns(&batchv1beta1.CronJob{}).
+		Owns(&schedulingv1.PriorityClass{}).
+		Owns(&autoscalingv2beta2.HorizontalPodAutoscaler{}).
+		Owns(&policyv1beta1.PodDisruptionBudget{}).
 		Owns(&operatorsv1.OperatorGroup{}).
 		Owns(&operatorsv1alpha1.CatalogSource{}).
 		Owns(&operatorsv1alpha1.Subscription{}).
+		Watches(&source.Informer{
+			Informer: configMapInformer,
+		}, &handler.EnqueueRequestForOwner{
+			OwnerType:    &addonsv1alpha1.Addon{},
+			IsController: true,
+		}).
 		Watches(&source.Kind{
 			Type: &operatorsv1alpha1.ClusterServiceVersion{},
-		}, r.csvEventHandler).
-		Complete(r)
+		}, r.csvEventHandler)
+
+	routeInformer, err := newRouteInformer(mgr)
+	if err != nil {
+		return err
+	}
+	if routeInformer != nil {
+		b = b.Watches(&source.Informer{
+			Informer: routeInformer,
+		}, &handler.EnqueueRequestForOwner{
+			OwnerType:    &addonsv1alpha1.Addon{},
+			IsController: true,
+		})
+	}
+
+	return b.Complete(r)
 }
 
 // AddonReconciler/Controller entrypoint
@@ -70,9 +128,18 @@ func (r *AddonReconciler) Reconcile(
 		// Clear from CSV Event Handler
 		r.csvEventHandler.Free(addon)
 
-		if controllerutil.ContainsFinalizer(addon, cacheFinalizer) {
-			controllerutil.RemoveFinalizer(addon, cacheFinalizer)
-			if err := r.Update(ctx, addon); err != nil {
+		if controllerutil.ContainsFinalizer(addon, r.cacheFinalizer()) {
+			// Ensure cleanup of webhook configurations registered for this Addon
+			if err := r.ensureDeletionOfWebhookConfigurations(ctx, addon); err != nil {
+				return ctrl.Result{}, fmt.Errorf("failed to ensure deletion of webhook configurations: %w", err)
+			}
+
+			// Ensure cleanup of cluster-scoped objects labeled for this Addon
+			if err := r.ensureDeletionOfLabeledObjects(ctx, addon); err != nil {
+				return ctrl.Result{}, fmt.Errorf("failed to ensure deletion of labeled objects: %w", err)
+			}
+
+			if err := r.removeCacheFinalizer(ctx, addon); err != nil {
 				return ctrl.Result{}, fmt.Errorf("failed to remove finalizer: %w", err)
 			}
 		}
@@ -84,82 +151,359 @@ func (r *AddonReconciler) Reconcile(
 		return ctrl.Result{}, r.reportTerminationStatus(ctx, addon)
 	}
 
-	// Phase 0.
-	// Ensure cache finalizer
-	if !controllerutil.ContainsFinalizer(addon, cacheFinalizer) {
-		controllerutil.AddFinalizer(addon, cacheFinalizer)
-		if err := r.Update(ctx, addon); err != nil {
-			return ctrl.Result{}, fmt.Errorf("failed to add finalizer: %w", err)
-		}
-	}
+	var (
+		catalogSource   *operatorsv1alpha1.CatalogSource
+		installedCSVKey client.ObjectKey
+		currentCSVKey   client.ObjectKey
+	)
+	return r.runPhasesWithTimeout(ctx, log, addon,
+		// Phase 0.
+		// Ensure cache finalizer
+		func(ctx context.Context) phaseResult {
+			if err := r.ensureCacheFinalizer(ctx, addon); err != nil {
+				return resultError(fmt.Errorf("failed to add finalizer: %w", err))
+			}
+			return resultDone()
+		},
 
-	// Phase 1.
-	// Ensure wanted namespaces
-	if stopAndRetry, err := r.ensureWantedNamespaces(ctx, addon); err != nil {
-		return ctrl.Result{}, fmt.Errorf("failed to ensure wanted Namespaces: %w", err)
-	} else if stopAndRetry {
-		return ctrl.Result{
-			RequeueAfter: defaultRetryAfterTime,
-		}, nil
-	}
+		// Phase 1.
+		// Observe maintenance window
+		func(ctx context.Context) phaseResult {
+			requeueAfter, err := r.observeMaintenanceWindow(ctx, addon)
+			if err != nil {
+				return resultError(fmt.Errorf("failed to observe maintenance window: %w", err))
+			}
+			return resultRequeueAfter(requeueAfter)
+		},
 
-	// Phase 2.
-	// Ensure unwanted namespaces are removed
-	if err := r.ensureDeletionOfUnwantedNamespaces(ctx, addon); err != nil {
-		return ctrl.Result{}, fmt.Errorf("failed to ensure deletion of unwanted Namespaces: %w", err)
-	}
+		// Phase 2.
+		// Observe required feature gates
+		func(ctx context.Context) phaseResult {
+			if requeue, err := r.observeRequiredFeatureGates(ctx, addon); err != nil {
+				return resultError(fmt.Errorf("failed to observe required feature gates: %w", err))
+			} else if requeue {
+				return resultStopAndRequeueAfter(defaultRetryAfterTime)
+			}
+			return resultDone()
+		},
 
-	// Phase 3.
-	// Ensure OperatorGroup
-	if stop, err := r.ensureOperatorGroup(ctx, log, addon); err != nil {
-		return ctrl.Result{}, fmt.Errorf("failed to ensure OperatorGroup: %w", err)
-	} else if stop {
-		return ctrl.Result{}, nil
-	}
+		// Phase 3.
+		// Ensure wanted namespaces
+		func(ctx context.Context) phaseResult {
+			if stopAndRetry, err := r.ensureWantedNamespaces(ctx, addon); err != nil {
+				return resultError(fmt.Errorf("failed to ensure wanted Namespaces: %w", err))
+			} else if stopAndRetry {
+				return resultStopAndRequeueAfter(defaultRetryAfterTime)
+			}
+			return resultDone()
+		},
 
-	// Phase 4.
-	ensureResult, catalogSource, err := r.ensureCatalogSource(ctx, log, addon)
-	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("failed to ensure CatalogSource: %w", err)
-	}
-	switch ensureResult {
-	case ensureCatalogSourceResultRetry:
-		log.Info("requeuing", "reason", "catalogsource unready")
-		return ctrl.Result{
-			RequeueAfter: defaultRetryAfterTime,
-		}, nil
-	case ensureCatalogSourceResultStop:
-		return ctrl.Result{}, nil
-	}
+		// Phase 4.
+		// Ensure monitoring labels on namespaces
+		func(ctx context.Context) phaseResult {
+			if err := r.ensureMonitoringLabels(ctx, addon); err != nil {
+				return resultError(fmt.Errorf("failed to ensure monitoring labels: %w", err))
+			}
+			return resultDone()
+		},
 
-	// Phase 5.
-	// Ensure Subscription for this Addon.
-	currentCSVKey, requeue, err := r.ensureSubscription(
-		ctx, log.WithName("phase-ensure-subscription"),
-		addon, catalogSource)
-	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("failed to ensure Subscription: %w", err)
-	} else if requeue {
-		return ctrl.Result{
-			RequeueAfter: defaultRetryAfterTime,
-		}, nil
-	}
+		// Phase 5.
+		// Ensure unwanted namespaces are removed
+		func(ctx context.Context) phaseResult {
+			if err := r.ensureDeletionOfUnwantedNamespaces(ctx, addon); err != nil {
+				return resultError(fmt.Errorf("failed to ensure deletion of unwanted Namespaces: %w", err))
+			}
+			return resultDone()
+		},
 
-	// Phase 6.
-	// Observe current csv
-	if requeue, err := r.observeCurrentCSV(ctx, addon, currentCSVKey); err != nil {
-		return ctrl.Result{}, fmt.Errorf("failed to observe current CSV: %w", err)
-	} else if requeue {
-		log.Info("requeuing", "reason", "csv unready")
-		return ctrl.Result{
-			RequeueAfter: defaultRetryAfterTime,
-		}, nil
-	}
+		// Phase 6.
+		// Ensure OperatorGroup
+		func(ctx context.Context) phaseResult {
+			if stop, err := r.ensureOperatorGroup(ctx, log, addon); err != nil {
+				return resultError(fmt.Errorf("failed to ensure OperatorGroup: %w", err))
+			} else if stop {
+				return resultStop()
+			}
+			return resultDone()
+		},
 
-	// After last phase and if everything is healthy
-	if err = r.reportReadinessStatus(ctx, addon); err != nil {
-		return ctrl.Result{}, fmt.Errorf("failed to report readiness status: %w", err)
-	}
+		// Phase 7.
+		// Ensure ServiceAccount
+		func(ctx context.Context) phaseResult {
+			if err := r.ensureServiceAccount(ctx, addon); err != nil {
+				return resultError(fmt.Errorf("failed to ensure ServiceAccount: %w", err))
+			}
+			return resultDone()
+		},
+
+		// Phase 8.
+		// Ensure SecurityContextConstraints are granted
+		func(ctx context.Context) phaseResult {
+			if stop, err := r.ensureSCCRoleBindings(ctx, addon); err != nil {
+				return resultError(fmt.Errorf("failed to ensure SCC RoleBindings: %w", err))
+			} else if stop {
+				return resultStop()
+			}
+			return resultDone()
+		},
+
+		// Phase 9.
+		// Ensure ClusterRoleBindings
+		func(ctx context.Context) phaseResult {
+			if stop, err := r.ensureClusterRoleBindings(ctx, addon); err != nil {
+				return resultError(fmt.Errorf("failed to ensure ClusterRoleBindings: %w", err))
+			} else if stop {
+				return resultStop()
+			}
+			return resultDone()
+		},
+
+		// Phase 10.
+		// Ensure LimitRange
+		func(ctx context.Context) phaseResult {
+			if stop, err := r.ensureLimitRange(ctx, addon); err != nil {
+				return resultError(fmt.Errorf("failed to ensure LimitRange: %w", err))
+			} else if stop {
+				return resultStop()
+			}
+			return resultDone()
+		},
 
-	return ctrl.Result{}, nil
+		// Phase 11.
+		// Ensure ConfigMaps
+		func(ctx context.Context) phaseResult {
+			if stop, err := r.ensureConfigMaps(ctx, addon); err != nil {
+				return resultError(fmt.Errorf("failed to ensure ConfigMaps: %w", err))
+			} else if stop {
+				return resultStop()
+			}
+			return resultDone()
+		},
+
+		// Phase 12.
+		// Ensure generated Secrets
+		func(ctx context.Context) phaseResult {
+			if stop, err := r.ensureGeneratedSecrets(ctx, addon); err != nil {
+				return resultError(fmt.Errorf("failed to ensure generated Secrets: %w", err))
+			} else if stop {
+				return resultStop()
+			}
+			return resultDone()
+		},
+
+		// Phase 13.
+		// Observe certificate expiry
+		func(ctx context.Context) phaseResult {
+			requeueAfter, err := r.observeCertificateExpiry(ctx, addon)
+			if err != nil {
+				return resultError(fmt.Errorf("failed to observe certificate expiry: %w", err))
+			}
+			return resultRequeueAfter(requeueAfter)
+		},
+
+		// Phase 14.
+		// Ensure EgressNetworkPolicy
+		func(ctx context.Context) phaseResult {
+			if stop, err := r.ensureEgressNetworkPolicy(ctx, addon); err != nil {
+				return resultError(fmt.Errorf("failed to ensure EgressNetworkPolicy: %w", err))
+			} else if stop {
+				return resultStop()
+			}
+			return resultDone()
+		},
+
+		// Phase 15.
+		// Ensure Routes
+		func(ctx context.Context) phaseResult {
+			if stop, err := r.ensureRoutes(ctx, addon); err != nil {
+				return resultError(fmt.Errorf("failed to ensure Routes: %w", err))
+			} else if stop {
+				return resultStop()
+			}
+			return resultDone()
+		},
+
+		// Phase 16.
+		// Ensure CronJobs
+		func(ctx context.Context) phaseResult {
+			if stop, err := r.ensureCronJobs(ctx, addon); err != nil {
+				return resultError(fmt.Errorf("failed to ensure CronJobs: %w", err))
+			} else if stop {
+				return resultStop()
+			}
+			return resultDone()
+		},
+
+		// Phase 17.
+		// Ensure PriorityClass
+		func(ctx context.Context) phaseResult {
+			ensureResult, err := r.ensurePriorityClass(ctx, addon)
+			if err != nil {
+				return resultError(fmt.Errorf("failed to ensure PriorityClass: %w", err))
+			}
+			switch ensureResult {
+			case ensurePriorityClassResultRetry:
+				log.Info("requeuing", "reason", "PriorityClass missing or collided")
+				return resultStopAndRequeueAfter(defaultRetryAfterTime)
+			case ensurePriorityClassResultStop:
+				return resultStop()
+			}
+			return resultDone()
+		},
+
+		// Phase 18.
+		// Ensure CatalogSource
+		func(ctx context.Context) phaseResult {
+			var (
+				ensureResult ensureCatalogSourceResult
+				err          error
+			)
+			ensureResult, catalogSource, err = r.ensureCatalogSource(ctx, log, addon)
+			if err != nil {
+				return resultError(fmt.Errorf("failed to ensure CatalogSource: %w", err))
+			}
+			switch ensureResult {
+			case ensureCatalogSourceResultRetry:
+				log.Info("requeuing", "reason", "catalogsource unready")
+				return resultStopAndRequeueAfter(defaultRetryAfterTime)
+			case ensureCatalogSourceResultStop:
+				return resultStop()
+			}
+			return resultDone()
+		},
+
+		// Phase 19.
+		// Ensure Subscription for this Addon.
+		func(ctx context.Context) phaseResult {
+			var (
+				requeue bool
+				err     error
+			)
+			installedCSVKey, currentCSVKey, requeue, err = r.ensureSubscription(
+				ctx, log.WithName("phase-ensure-subscription"),
+				addon, catalogSource)
+			if err != nil {
+				return resultError(fmt.Errorf("failed to ensure Subscription: %w", err))
+			} else if requeue {
+				return resultStopAndRequeueAfter(defaultRetryAfterTime)
+			}
+			return resultDone()
+		},
+
+		// Phase 20.
+		// Observe available upgrades
+		func(ctx context.Context) phaseResult {
+			if err := r.observeUpgradeAvailable(ctx, addon); err != nil {
+				return resultError(fmt.Errorf("failed to observe available upgrades: %w", err))
+			}
+			return resultDone()
+		},
+
+		// Phase 21.
+		// Observe deprecations from the catalog
+		func(ctx context.Context) phaseResult {
+			if err := r.observeDeprecation(ctx, addon); err != nil {
+				return resultError(fmt.Errorf("failed to observe deprecation: %w", err))
+			}
+			return resultDone()
+		},
+
+		// Phase 22.
+		// Observe current csv
+		func(ctx context.Context) phaseResult {
+			if requeue, err := r.observeCurrentCSV(ctx, addon, installedCSVKey, currentCSVKey); err != nil {
+				return resultError(fmt.Errorf("failed to observe current CSV: %w", err))
+			} else if requeue {
+				log.Info("requeuing", "reason", "csv unready")
+				return resultStopAndRequeueAfter(defaultRetryAfterTime)
+			}
+			return resultDone()
+		},
+
+		// Phase 23.
+		// Observe copied csvs
+		func(ctx context.Context) phaseResult {
+			requeueAfter, err := r.observeCopiedCSVs(ctx, addon, installedCSVKey)
+			if err != nil {
+				return resultError(fmt.Errorf("failed to observe copied CSVs: %w", err))
+			}
+			return resultRequeueAfter(requeueAfter)
+		},
+
+		// Phase 24.
+		// Ensure registry overrides on the Deployments of the installed csv
+		func(ctx context.Context) phaseResult {
+			if stop, err := r.ensureRegistryOverrides(ctx, addon, installedCSVKey); err != nil {
+				return resultError(fmt.Errorf("failed to ensure registry overrides: %w", err))
+			} else if stop {
+				return resultStop()
+			}
+			return resultDone()
+		},
+
+		// Phase 25.
+		// Ensure HorizontalPodAutoscaler
+		func(ctx context.Context) phaseResult {
+			ensureResult, err := r.ensureHPA(ctx, addon)
+			if err != nil {
+				return resultError(fmt.Errorf("failed to ensure HorizontalPodAutoscaler: %w", err))
+			}
+			switch ensureResult {
+			case ensureHPAResultRetry:
+				log.Info("requeuing", "reason", "HPA target missing")
+				return resultStopAndRequeueAfter(defaultRetryAfterTime)
+			case ensureHPAResultStop:
+				return resultStop()
+			}
+			return resultDone()
+		},
+
+		// Phase 26.
+		// Ensure VerticalPodAutoscaler
+		func(ctx context.Context) phaseResult {
+			ensureResult, err := r.ensureVPA(ctx, addon)
+			if err != nil {
+				return resultError(fmt.Errorf("failed to ensure VerticalPodAutoscaler: %w", err))
+			}
+			switch ensureResult {
+			case ensureVPAResultRetry:
+				log.Info("requeuing", "reason", "VPA CRD missing")
+				return resultStopAndRequeueAfter(defaultRetryAfterTime)
+			case ensureVPAResultStop:
+				return resultStop()
+			}
+			return resultDone()
+		},
+
+		// Phase 27.
+		// Ensure PodDisruptionBudget
+		func(ctx context.Context) phaseResult {
+			if stop, err := r.ensurePDB(ctx, addon); err != nil {
+				return resultError(fmt.Errorf("failed to ensure PodDisruptionBudget: %w", err))
+			} else if stop {
+				return resultStop()
+			}
+			return resultDone()
+		},
+
+		// Phase 28.
+		// Observe required deployments
+		func(ctx context.Context) phaseResult {
+			if requeue, err := r.observeRequiredDeployments(ctx, addon); err != nil {
+				return resultError(fmt.Errorf("failed to observe required Deployments: %w", err))
+			} else if requeue {
+				log.Info("requeuing", "reason", "workloads unready")
+				return resultStopAndRequeueAfter(defaultRetryAfterTime)
+			}
+			return resultDone()
+		},
+
+		// After last phase and if everything is healthy
+		func(ctx context.Context) phaseResult {
+			if err := r.reportReadinessStatus(ctx, addon); err != nil {
+				return resultError(fmt.Errorf("failed to report readiness status: %w", err))
+			}
+			return resultDone()
+		},
+	)
 }
@@ -3,10 +3,13 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
 )
@@ -22,7 +25,7 @@ func (r *AddonReconciler) reportReadinessStatus(
 	})
 	addon.Status.ObservedGeneration = addon.Generation
 	addon.Status.Phase = addonsv1alpha1.PhaseReady
-	return r.Status().Update(ctx, addon)
+	return r.updateAddonStatus(ctx, addon)
 }
 
 // Report Addon status to communicate that the Addon is terminating
@@ -36,7 +39,23 @@ func (r *AddonReconciler) reportTerminationStatus(
 	})
 	addon.Status.ObservedGeneration = addon.Generation
 	addon.Status.Phase = addonsv1alpha1.PhaseTerminating
-	return r.Status().Update(ctx, addon)
+	return r.updateAddonStatus(ctx, addon)
+}
+
+// Report Addon status to communicate that reconciliation took too long
+func (r *AddonReconciler) reportReconcileTimeout(
+	ctx context.Context, addon *addonsv1alpha1.Addon) error {
+	meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+		Type:   addonsv1alpha1.Available,
+		Status: metav1.ConditionFalse,
+		Reason: addonsv1alpha1.AddonReasonReconcileTimeout,
+		Message: fmt.Sprintf(
+			"Reconciliation did not finish within %s", r.reconcileTimeout()),
+		ObservedGeneration: addon.Generation,
+	})
+	addon.Status.ObservedGeneration = addon.Generation
+	addon.Status.Phase = addonsv1alpha1.PhasePending
+	return r.updateAddonStatus(ctx, addon)
 }
 
 // Report Addon status to communicate that the resource is misconfigured
@@ -52,7 +71,7 @@ func (r *AddonReconciler) reportConfigurationError(
 	})
 	addon.Status.ObservedGeneration = addon.Generation
 	addon.Status.Phase = addonsv1alpha1.PhaseError
-	return r.Status().Update(ctx, addon)
+	return r.updateAddonStatus(ctx, addon)
 }
 
 // Validate addon.Spec.Install then extract
@@ -106,3 +125,83 @@ func (r *AddonReconciler) parseAddonInstallConfig(
 
 	return targetNamespace, catalogSourceImage, false, nil
 }
+
+// Returns the install parameters common to all OLM install types of the given Addon.
+// The install config has to be validated via parseAddonInstallConfig beforehand.
+func getCommonInstallOptions(addon *addonsv1alpha1.Addon) (
+	commonInstallOptions addonsv1alpha1.AddonInstallOLMCommon) {
+	switch addon.Spec.Install.Type {
+	case addonsv1alpha1.OLMAllNamespaces:
+		commonInstallOptions = addon.Spec.Install.
+			OLMAllNamespaces.AddonInstallOLMCommon
+	case addonsv1alpha1.OLMOwnNamespace:
+		commonInstallOptions = addon.Spec.Install.
+			OLMOwnNamespace.AddonInstallOLMCommon
+	}
+	return commonInstallOptions
+}
+
+// Returns the configured APIReader or the cached Client.
+func (r *AddonReconciler) apiReader() client.Reader {
+	if r.APIReader == nil {
+		return r.Client
+	}
+	return r.APIReader
+}
+
+// Number of attempts to update the status of an Addon on conflicts.
+const addonStatusUpdateAttempts = 5
+
+// Updates the status of the given Addon.
+// On conflicts the latest Addon is fetched and the status is written on top of it,
+// so status changes are not lost when the Addon was modified concurrently.
+func (r *AddonReconciler) updateAddonStatus(
+	ctx context.Context, addon *addonsv1alpha1.Addon) error {
+	status := addon.Status.DeepCopy()
+
+	err := r.Status().Update(ctx, addon)
+	for i := 1; i < addonStatusUpdateAttempts && k8sApiErrors.IsConflict(err); i++ {
+		latestAddon := &addonsv1alpha1.Addon{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(addon), latestAddon); err != nil {
+			return fmt.Errorf("getting latest Addon: %w", err)
+		}
+
+		status.DeepCopyInto(&latestAddon.Status)
+		if err = r.Status().Update(ctx, latestAddon); err == nil {
+			latestAddon.DeepCopyInto(addon)
+		}
+	}
+	return err
+}
+
+const (
+	// Number of attempts to reconcile OLM objects, when racing with OLM itself.
+	olmObjectReconcileAttempts = 3
+	// Initial backoff between attempts to reconcile OLM objects, doubled after each attempt.
+	olmObjectReconcileBackoff = 100 * time.Millisecond
+)
+
+// Retries the given reconcile func with backoff, if creating or updating an object
+// raced with the OLM controllers or a stale cache and failed with AlreadyExists or Conflict.
+// The reconcile func is expected to re-fetch the object and reconcile it towards the desired state.
+func retryOnOLMObjectConflict(ctx context.Context, reconcile func() error) error {
+	backoff := olmObjectReconcileBackoff
+
+	var err error
+	for i := 0; i < olmObjectReconcileAttempts; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		err = reconcile()
+		if !k8sApiErrors.IsAlreadyExists(err) && !k8sApiErrors.IsConflict(err) {
+			return err
+		}
+	}
+	return err
+}
@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestUpdateAddonStatus(t *testing.T) {
+	t.Run("retries on conflict with latest Addon", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithCatalogSourceImage()
+		addon.ResourceVersion = "1"
+		addon.Status.Phase = addonsv1alpha1.PhaseReady
+
+		conflictErr := k8sApiErrors.NewConflict(
+			schema.GroupResource{Group: "addons.managed.openshift.io", Resource: "addons"},
+			addon.Name, nil)
+		c.StatusMock.
+			On("Update", mock.Anything, mock.MatchedBy(func(a *addonsv1alpha1.Addon) bool {
+				return a.ResourceVersion == "1"
+			}), mock.Anything).
+			Return(conflictErr).
+			Once()
+		c.On("Get", mock.Anything, client.ObjectKey{Name: addon.Name},
+			mock.IsType(&addonsv1alpha1.Addon{})).
+			Run(func(args mock.Arguments) {
+				latestAddon := args.Get(2).(*addonsv1alpha1.Addon)
+				newTestAddonWithCatalogSourceImage().DeepCopyInto(latestAddon)
+				latestAddon.ResourceVersion = "2"
+			}).
+			Return(nil)
+		var updatedAddon *addonsv1alpha1.Addon
+		c.StatusMock.
+			On("Update", mock.Anything, mock.MatchedBy(func(a *addonsv1alpha1.Addon) bool {
+				return a.ResourceVersion == "2"
+			}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				updatedAddon = args.Get(1).(*addonsv1alpha1.Addon)
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.updateAddonStatus(ctx, addon)
+		require.NoError(t, err)
+
+		c.AssertExpectations(t)
+		c.StatusMock.AssertNumberOfCalls(t, "Update", 2)
+		if assert.NotNil(t, updatedAddon) {
+			// status is not dropped on the re-fetched Addon
+			assert.Equal(t, addonsv1alpha1.PhaseReady, updatedAddon.Status.Phase)
+		}
+		assert.Equal(t, "2", addon.ResourceVersion)
+	})
+
+	t.Run("gives up after repeated conflicts", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithCatalogSourceImage()
+
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(k8sApiErrors.NewConflict(
+				schema.GroupResource{Group: "addons.managed.openshift.io", Resource: "addons"},
+				addon.Name, nil))
+		c.On("Get", mock.Anything, client.ObjectKey{Name: addon.Name},
+			mock.IsType(&addonsv1alpha1.Addon{})).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.updateAddonStatus(ctx, addon)
+		assert.True(t, k8sApiErrors.IsConflict(err))
+		c.StatusMock.AssertNumberOfCalls(t, "Update", addonStatusUpdateAttempts)
+	})
+}
+
+func TestRetryOnOLMObjectConflict(t *testing.T) {
+	ctx := context.Background()
+	alreadyExistsErr := k8sApiErrors.NewAlreadyExists(
+		schema.GroupResource{Resource: "subscriptions"}, "addon-1")
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		var calls int
+		err := retryOnOLMObjectConflict(ctx, func() error {
+			calls++
+			return alreadyExistsErr
+		})
+		assert.True(t, k8sApiErrors.IsAlreadyExists(err))
+		assert.Equal(t, olmObjectReconcileAttempts, calls)
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		timeoutErr := k8sApiErrors.NewTimeoutError("for testing", 1)
+
+		var calls int
+		err := retryOnOLMObjectConflict(ctx, func() error {
+			calls++
+			return timeoutErr
+		})
+		assert.Equal(t, timeoutErr, err)
+		assert.Equal(t, 1, calls)
+	})
+}
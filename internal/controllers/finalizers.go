@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+// Returns the configured cache finalizer name or the default.
+func (r *AddonReconciler) cacheFinalizer() string {
+	if len(r.CacheFinalizer) == 0 {
+		return DefaultCacheFinalizer
+	}
+	return r.CacheFinalizer
+}
+
+// Ensures the cache finalizer is present on the given Addon.
+func (r *AddonReconciler) ensureCacheFinalizer(
+	ctx context.Context, addon *addonsv1alpha1.Addon) error {
+	if controllerutil.ContainsFinalizer(addon, r.cacheFinalizer()) {
+		return nil
+	}
+
+	controllerutil.AddFinalizer(addon, r.cacheFinalizer())
+	return r.Update(ctx, addon)
+}
+
+// Ensures the cache finalizer is removed from the given Addon.
+func (r *AddonReconciler) removeCacheFinalizer(
+	ctx context.Context, addon *addonsv1alpha1.Addon) error {
+	if !controllerutil.ContainsFinalizer(addon, r.cacheFinalizer()) {
+		return nil
+	}
+
+	controllerutil.RemoveFinalizer(addon, r.cacheFinalizer())
+	return r.Update(ctx, addon)
+}
@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestCacheFinalizer(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		r := &AddonReconciler{}
+		assert.Equal(t, DefaultCacheFinalizer, r.cacheFinalizer())
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		r := &AddonReconciler{CacheFinalizer: "test.example.com/cache"}
+		assert.Equal(t, "test.example.com/cache", r.cacheFinalizer())
+	})
+}
+
+func TestEnsureCacheFinalizer(t *testing.T) {
+	const customFinalizer = "test.example.com/cache"
+
+	t.Run("adds custom finalizer", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client:         c,
+			Scheme:         newTestSchemeWithAddonsv1alpha1(),
+			CacheFinalizer: customFinalizer,
+		}
+
+		c.On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		addon := newTestAddonWithoutNamespace()
+		ctx := context.Background()
+		err := r.ensureCacheFinalizer(ctx, addon)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{customFinalizer}, addon.Finalizers)
+		c.AssertNumberOfCalls(t, "Update", 1)
+	})
+
+	t.Run("honors existing custom finalizer", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client:         c,
+			Scheme:         newTestSchemeWithAddonsv1alpha1(),
+			CacheFinalizer: customFinalizer,
+		}
+
+		addon := newTestAddonWithoutNamespace()
+		controllerutil.AddFinalizer(addon, customFinalizer)
+		ctx := context.Background()
+		err := r.ensureCacheFinalizer(ctx, addon)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{customFinalizer}, addon.Finalizers)
+		c.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRemoveCacheFinalizer(t *testing.T) {
+	const customFinalizer = "test.example.com/cache"
+
+	t.Run("removes custom finalizer", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client:         c,
+			Scheme:         newTestSchemeWithAddonsv1alpha1(),
+			CacheFinalizer: customFinalizer,
+		}
+
+		c.On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		addon := newTestAddonWithoutNamespace()
+		controllerutil.AddFinalizer(addon, DefaultCacheFinalizer)
+		controllerutil.AddFinalizer(addon, customFinalizer)
+		ctx := context.Background()
+		err := r.removeCacheFinalizer(ctx, addon)
+		require.NoError(t, err)
+
+		// finalizers of other operator variants are left alone
+		assert.Equal(t, []string{DefaultCacheFinalizer}, addon.Finalizers)
+		c.AssertNumberOfCalls(t, "Update", 1)
+	})
+
+	t.Run("no-op without custom finalizer", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client:         c,
+			Scheme:         newTestSchemeWithAddonsv1alpha1(),
+			CacheFinalizer: customFinalizer,
+		}
+
+		addon := newTestAddonWithoutNamespace()
+		controllerutil.AddFinalizer(addon, DefaultCacheFinalizer)
+		ctx := context.Background()
+		err := r.removeCacheFinalizer(ctx, addon)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{DefaultCacheFinalizer}, addon.Finalizers)
+		c.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
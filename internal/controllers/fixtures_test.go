@@ -11,6 +11,7 @@ import (
 	utilpointer "k8s.io/utils/pointer"
 
 	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	"github.com/openshift/addon-operator/internal/testutil"
 )
 
 func newTestSchemeWithAddonsv1alpha1() *runtime.Scheme {
@@ -136,21 +137,5 @@ func newTestCatalogSourceWithoutOwner() *operatorsv1alpha1.CatalogSource {
 }
 
 func newTestAddonWithCatalogSourceImage() *addonsv1alpha1.Addon {
-	return &addonsv1alpha1.Addon{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: "addon-1",
-			UID:  "addon-uid",
-		},
-		Spec: addonsv1alpha1.AddonSpec{
-			Install: addonsv1alpha1.AddonInstallSpec{
-				Type: addonsv1alpha1.OLMOwnNamespace,
-				OLMOwnNamespace: &addonsv1alpha1.AddonInstallOLMOwnNamespace{
-					AddonInstallOLMCommon: addonsv1alpha1.AddonInstallOLMCommon{
-						CatalogSourceImage: "quay.io/osd-addons/test:sha256:04864220677b2ed6244f2e0d421166df908986700647595ffdb6fd9ca4e5098a",
-						Namespace:          "addon-1",
-					},
-				},
-			},
-		},
-	}
+	return testutil.NewAddon()
 }
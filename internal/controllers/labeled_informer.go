@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/metadata"
+	toolscache "k8s.io/client-go/tools/cache"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// Creates an informer for the metadata of the given resource,
+// that only watches objects carrying the common managed-by label.
+// The manager cache would keep every object of the resource in the cluster in memory,
+// while drift detection of objects created for Addons only needs their owner references.
+// Objects of the resource have to be read through the APIReader.
+func newCommonLabelInformer(
+	mgr ctrl.Manager, gvr schema.GroupVersionResource) (toolscache.SharedIndexInformer, error) {
+	metadataClient, err := metadata.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, fmt.Errorf("creating metadata client: %w", err)
+	}
+
+	selector := labels.Set{commonManagedByLabel: commonManagedByValue}.String()
+	informer := toolscache.NewSharedIndexInformer(&toolscache.ListWatch{
+		ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+			opts.LabelSelector = selector
+			return metadataClient.Resource(gvr).List(context.Background(), opts)
+		},
+		WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+			opts.LabelSelector = selector
+			return metadataClient.Resource(gvr).Watch(context.Background(), opts)
+		},
+	}, &metav1.PartialObjectMetadata{}, 0, toolscache.Indexers{})
+
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		informer.Run(ctx.Done())
+		return nil
+	})); err != nil {
+		return nil, fmt.Errorf("adding informer for %s: %w", gvr.Resource, err)
+	}
+	return informer, nil
+}
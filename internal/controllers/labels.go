@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 
 	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
@@ -27,3 +28,26 @@ func commonLabelsAsLabelSelector(addon *addonsv1alpha1.Addon) labels.Selector {
 	labelSet[commonInstanceLabel] = addon.Name
 	return labelSet.AsSelector()
 }
+
+// Copies the common labels of the desired object onto the current object,
+// so objects created without them are still found by label selector on cleanup.
+func reconcileCommonLabels(current, desired metav1.Object) (changed bool) {
+	currentLabels := current.GetLabels()
+	for _, label := range []string{commonManagedByLabel, commonInstanceLabel} {
+		wantedValue, ok := desired.GetLabels()[label]
+		if !ok {
+			continue
+		}
+		if currentValue, ok := currentLabels[label]; ok && currentValue == wantedValue {
+			continue
+		}
+
+		if currentLabels == nil {
+			currentLabels = map[string]string{}
+		}
+		currentLabels[label] = wantedValue
+		changed = true
+	}
+	current.SetLabels(currentLabels)
+	return changed
+}
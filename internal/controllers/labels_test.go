@@ -40,3 +40,39 @@ func TestCommonLabelsAsLabelSelector(t *testing.T) {
 		t.Fatal("selector is empty but should filter on common labels")
 	}
 }
+
+func TestReconcileCommonLabels(t *testing.T) {
+	addon := &addonsv1alpha1.Addon{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "test",
+		},
+	}
+	desired := &v1.ObjectMeta{
+		Labels: map[string]string{},
+	}
+	addCommonLabels(desired.Labels, addon)
+
+	current := &v1.ObjectMeta{
+		Labels: map[string]string{"other": "label"},
+	}
+	if !reconcileCommonLabels(current, desired) {
+		t.Error("missing common labels were not reported as changed")
+	}
+	if current.Labels[commonInstanceLabel] != addon.Name ||
+		current.Labels[commonManagedByLabel] != commonManagedByValue {
+		t.Error("common labels were not stamped on current object")
+	}
+	if current.Labels["other"] != "label" {
+		t.Error("unrelated labels were not kept")
+	}
+
+	if reconcileCommonLabels(current, desired) {
+		t.Error("present common labels were reported as changed")
+	}
+
+	unlabeled := &v1.ObjectMeta{}
+	if !reconcileCommonLabels(unlabeled, desired) ||
+		unlabeled.Labels[commonInstanceLabel] != addon.Name {
+		t.Error("common labels were not stamped on object without labels")
+	}
+}
@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+// Ensure cleanup of cluster-scoped objects that have been created for the given Addon resource.
+// Owner references already let the garbage collector delete these objects,
+// deleting them by their common labels is a backstop for objects that lost their owner reference.
+// Namespaced objects are removed together with the Namespaces of the Addon.
+func (r *AddonReconciler) ensureDeletionOfLabeledObjects(
+	ctx context.Context, addon *addonsv1alpha1.Addon) error {
+	listOpts := &client.ListOptions{
+		LabelSelector: commonLabelsAsLabelSelector(addon),
+	}
+
+	lists := []client.ObjectList{
+		&rbacv1.ClusterRoleBindingList{},
+		&schedulingv1.PriorityClassList{},
+		&corev1.NamespaceList{},
+	}
+	for _, list := range lists {
+		if err := r.List(ctx, list, listOpts); err != nil {
+			return fmt.Errorf("could not list %T: %w", list, err)
+		}
+
+		objs, err := meta.ExtractList(list)
+		if err != nil {
+			return fmt.Errorf("extracting %T: %w", list, err)
+		}
+		for _, obj := range objs {
+			err := r.Delete(ctx, obj.(client.Object))
+			// don't propagate error if the object is already gone
+			if err != nil && !k8sApiErrors.IsNotFound(err) {
+				return fmt.Errorf("could not delete %T: %w", obj, err)
+			}
+		}
+	}
+
+	return nil
+}
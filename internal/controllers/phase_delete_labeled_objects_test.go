@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestEnsureDeletionOfLabeledObjects(t *testing.T) {
+	addon := newTestAddonWithoutNamespace()
+
+	labeledClusterRoleBinding := rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "labeled-crb",
+			Labels: map[string]string{},
+		},
+	}
+	addCommonLabels(labeledClusterRoleBinding.Labels, addon)
+	unlabeledClusterRoleBinding := rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "unlabeled-crb",
+		},
+	}
+	labeledPriorityClass := schedulingv1.PriorityClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "labeled-pc",
+			Labels: map[string]string{},
+		},
+	}
+	addCommonLabels(labeledPriorityClass.Labels, addon)
+	labeledNamespace := corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "labeled-ns",
+			Labels: map[string]string{},
+		},
+	}
+	addCommonLabels(labeledNamespace.Labels, addon)
+
+	c := testutil.NewClient()
+	// simulate server side label selection
+	c.On("List", mock.Anything, mock.IsType(&rbacv1.ClusterRoleBindingList{}), mock.Anything).
+		Run(func(args mock.Arguments) {
+			list := args.Get(1).(*rbacv1.ClusterRoleBindingList)
+			selector := labelSelectorFromListOptions(args.Get(2).([]client.ListOption))
+			for _, obj := range []rbacv1.ClusterRoleBinding{
+				labeledClusterRoleBinding, unlabeledClusterRoleBinding,
+			} {
+				if selector.Matches(labels.Set(obj.Labels)) {
+					list.Items = append(list.Items, obj)
+				}
+			}
+		}).
+		Return(nil)
+	c.On("List", mock.Anything, mock.IsType(&schedulingv1.PriorityClassList{}), mock.Anything).
+		Run(func(args mock.Arguments) {
+			list := args.Get(1).(*schedulingv1.PriorityClassList)
+			list.Items = append(list.Items, labeledPriorityClass)
+		}).
+		Return(nil)
+	c.On("List", mock.Anything, mock.IsType(&corev1.NamespaceList{}), mock.Anything).
+		Run(func(args mock.Arguments) {
+			list := args.Get(1).(*corev1.NamespaceList)
+			list.Items = append(list.Items, labeledNamespace)
+		}).
+		Return(nil)
+	c.On("Delete", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil)
+
+	r := &AddonReconciler{
+		Client: c,
+		Log:    testutil.NewLogger(t),
+		Scheme: newTestSchemeWithAddonsv1alpha1(),
+	}
+
+	ctx := context.Background()
+	err := r.ensureDeletionOfLabeledObjects(ctx, addon)
+	require.NoError(t, err)
+	c.AssertExpectations(t)
+	c.AssertNumberOfCalls(t, "Delete", 3)
+	c.AssertCalled(t, "Delete", mock.Anything,
+		mock.MatchedBy(func(obj *rbacv1.ClusterRoleBinding) bool {
+			return obj.Name == labeledClusterRoleBinding.Name
+		}), mock.Anything)
+	c.AssertCalled(t, "Delete", mock.Anything,
+		mock.MatchedBy(func(obj *schedulingv1.PriorityClass) bool {
+			return obj.Name == labeledPriorityClass.Name
+		}), mock.Anything)
+	c.AssertCalled(t, "Delete", mock.Anything,
+		mock.MatchedBy(func(obj *corev1.Namespace) bool {
+			return obj.Name == labeledNamespace.Name
+		}), mock.Anything)
+	c.AssertNotCalled(t, "Delete", mock.Anything,
+		mock.MatchedBy(func(obj *rbacv1.ClusterRoleBinding) bool {
+			return obj.Name == unlabeledClusterRoleBinding.Name
+		}), mock.Anything)
+}
+
+func TestEnsureDeletionOfLabeledObjects_WithClientError(t *testing.T) {
+	timeoutErr := k8sApiErrors.NewTimeoutError("for testing", 1)
+
+	c := testutil.NewClient()
+	c.On("List", mock.Anything, mock.IsType(&rbacv1.ClusterRoleBindingList{}), mock.Anything).
+		Return(timeoutErr)
+
+	r := &AddonReconciler{
+		Client: c,
+		Log:    testutil.NewLogger(t),
+		Scheme: newTestSchemeWithAddonsv1alpha1(),
+	}
+
+	ctx := context.Background()
+	err := r.ensureDeletionOfLabeledObjects(ctx, newTestAddonWithoutNamespace())
+	require.EqualError(t, errors.Unwrap(err), timeoutErr.Error())
+	c.AssertExpectations(t)
+}
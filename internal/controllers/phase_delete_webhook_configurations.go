@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+// Ensure cleanup of webhook configurations that have been registered for the given Addon resource.
+// Webhook configurations have to carry the common labels of the Addon to be cleaned up,
+// so orphaned configurations don't outlive the Addon if its CSV was removed uncleanly.
+// They are listed through the APIReader, as they are not watched and only needed on deletion.
+func (r *AddonReconciler) ensureDeletionOfWebhookConfigurations(
+	ctx context.Context, addon *addonsv1alpha1.Addon) error {
+	listOpts := &client.ListOptions{
+		LabelSelector: client.MatchingLabelsSelector{
+			Selector: commonLabelsAsLabelSelector(addon),
+		},
+	}
+
+	validatingWebhookConfigurations := &admissionregistrationv1.ValidatingWebhookConfigurationList{}
+	if err := r.apiReader().List(ctx, validatingWebhookConfigurations, listOpts); err != nil {
+		return fmt.Errorf("could not list ValidatingWebhookConfigurations: %w", err)
+	}
+	for i := range validatingWebhookConfigurations.Items {
+		err := r.Delete(ctx, &validatingWebhookConfigurations.Items[i])
+		// don't propagate error if the object is already gone
+		if err != nil && !k8sApiErrors.IsNotFound(err) {
+			return fmt.Errorf("could not delete ValidatingWebhookConfiguration: %w", err)
+		}
+	}
+
+	mutatingWebhookConfigurations := &admissionregistrationv1.MutatingWebhookConfigurationList{}
+	if err := r.apiReader().List(ctx, mutatingWebhookConfigurations, listOpts); err != nil {
+		return fmt.Errorf("could not list MutatingWebhookConfigurations: %w", err)
+	}
+	for i := range mutatingWebhookConfigurations.Items {
+		err := r.Delete(ctx, &mutatingWebhookConfigurations.Items[i])
+		// don't propagate error if the object is already gone
+		if err != nil && !k8sApiErrors.IsNotFound(err) {
+			return fmt.Errorf("could not delete MutatingWebhookConfiguration: %w", err)
+		}
+	}
+
+	return nil
+}
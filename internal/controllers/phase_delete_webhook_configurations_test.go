@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestEnsureDeletionOfWebhookConfigurations(t *testing.T) {
+	addon := newTestAddonWithoutNamespace()
+
+	labeledValidating := admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "labeled-validating",
+			Labels: map[string]string{},
+		},
+	}
+	addCommonLabels(labeledValidating.Labels, addon)
+	unlabeledValidating := admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "unlabeled-validating",
+		},
+	}
+	labeledMutating := admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "labeled-mutating",
+			Labels: map[string]string{},
+		},
+	}
+	addCommonLabels(labeledMutating.Labels, addon)
+
+	c := testutil.NewClient()
+	apiReader := testutil.NewClient()
+	// simulate server side label selection
+	apiReader.On("List", mock.Anything, mock.IsType(&admissionregistrationv1.ValidatingWebhookConfigurationList{}), mock.Anything).
+		Run(func(args mock.Arguments) {
+			list := args.Get(1).(*admissionregistrationv1.ValidatingWebhookConfigurationList)
+			selector := labelSelectorFromListOptions(args.Get(2).([]client.ListOption))
+			for _, obj := range []admissionregistrationv1.ValidatingWebhookConfiguration{
+				labeledValidating, unlabeledValidating,
+			} {
+				if selector.Matches(labels.Set(obj.Labels)) {
+					list.Items = append(list.Items, obj)
+				}
+			}
+		}).
+		Return(nil)
+	apiReader.On("List", mock.Anything, mock.IsType(&admissionregistrationv1.MutatingWebhookConfigurationList{}), mock.Anything).
+		Run(func(args mock.Arguments) {
+			list := args.Get(1).(*admissionregistrationv1.MutatingWebhookConfigurationList)
+			list.Items = append(list.Items, labeledMutating)
+		}).
+		Return(nil)
+	c.On("Delete", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil)
+
+	r := &AddonReconciler{
+		Client:    c,
+		APIReader: apiReader,
+		Log:       testutil.NewLogger(t),
+		Scheme:    newTestSchemeWithAddonsv1alpha1(),
+	}
+
+	ctx := context.Background()
+	err := r.ensureDeletionOfWebhookConfigurations(ctx, addon)
+	require.NoError(t, err)
+	c.AssertExpectations(t)
+	apiReader.AssertExpectations(t)
+	// the cached client would start informers for all webhook configurations
+	c.AssertNotCalled(t, "List", mock.Anything, mock.Anything, mock.Anything)
+	c.AssertNumberOfCalls(t, "Delete", 2)
+	c.AssertCalled(t, "Delete", mock.Anything,
+		mock.MatchedBy(func(obj *admissionregistrationv1.ValidatingWebhookConfiguration) bool {
+			return obj.Name == labeledValidating.Name
+		}), mock.Anything)
+	c.AssertCalled(t, "Delete", mock.Anything,
+		mock.MatchedBy(func(obj *admissionregistrationv1.MutatingWebhookConfiguration) bool {
+			return obj.Name == labeledMutating.Name
+		}), mock.Anything)
+	c.AssertNotCalled(t, "Delete", mock.Anything,
+		mock.MatchedBy(func(obj *admissionregistrationv1.ValidatingWebhookConfiguration) bool {
+			return obj.Name == unlabeledValidating.Name
+		}), mock.Anything)
+}
+
+func TestEnsureDeletionOfWebhookConfigurations_WithClientError(t *testing.T) {
+	timeoutErr := k8sApiErrors.NewTimeoutError("for testing", 1)
+
+	c := testutil.NewClient()
+	c.On("List", mock.Anything, mock.IsType(&admissionregistrationv1.ValidatingWebhookConfigurationList{}), mock.Anything).
+		Return(timeoutErr)
+
+	r := &AddonReconciler{
+		Client: c,
+		Log:    testutil.NewLogger(t),
+		Scheme: newTestSchemeWithAddonsv1alpha1(),
+	}
+
+	ctx := context.Background()
+	err := r.ensureDeletionOfWebhookConfigurations(ctx, newTestAddonWithoutNamespace())
+	require.EqualError(t, errors.Unwrap(err), timeoutErr.Error())
+	c.AssertExpectations(t)
+}
+
+func labelSelectorFromListOptions(listOptions []client.ListOption) labels.Selector {
+	opts := &client.ListOptions{}
+	for _, opt := range listOptions {
+		opt.ApplyToList(opts)
+	}
+	if opts.LabelSelector == nil {
+		return labels.Everything()
+	}
+	return opts.LabelSelector
+}
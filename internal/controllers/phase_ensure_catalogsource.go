@@ -6,6 +6,7 @@ import (
 
 	"github.com/go-logr/logr"
 	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -44,6 +45,7 @@ func (r *AddonReconciler) ensureCatalogSource(
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      addon.Name,
 			Namespace: targetNamespace,
+			Labels:    map[string]string{},
 		},
 		Spec: operatorsv1alpha1.CatalogSourceSpec{
 			SourceType:  operatorsv1alpha1.SourceTypeGrpc,
@@ -61,13 +63,30 @@ func (r *AddonReconciler) ensureCatalogSource(
 
 	var observedCatalogSource *operatorsv1alpha1.CatalogSource
 	{
-		var err error
-		observedCatalogSource, err = reconcileCatalogSource(ctx, r.Client, catalogSource)
+		err := retryOnOLMObjectConflict(ctx, func() (err error) {
+			observedCatalogSource, err = reconcileCatalogSource(ctx, r.Client, catalogSource.DeepCopy())
+			return err
+		})
 		if err != nil {
 			return ensureCatalogSourceResultNil, nil, err
 		}
 	}
 
+	if observedCatalogSource.Status.GRPCConnectionState == nil ||
+		observedCatalogSource.Status.GRPCConnectionState.LastObservedState != "READY" {
+		// the registry Pod never gets ready, if its image can't be pulled
+		imageErr, err := r.getCatalogSourceImageError(ctx, observedCatalogSource)
+		if err != nil {
+			return ensureCatalogSourceResultNil, nil, err
+		}
+		if len(imageErr) > 0 {
+			if err := r.reportCatalogImageError(ctx, addon, imageErr); err != nil {
+				return ensureCatalogSourceResultNil, nil, err
+			}
+			return ensureCatalogSourceResultRetry, nil, nil
+		}
+	}
+
 	if observedCatalogSource.Status.GRPCConnectionState == nil {
 		err := r.reportCatalogSourceUnreadinessStatus(ctx, addon, observedCatalogSource, ".Status.GRPCConnectionState is nil")
 		if err != nil {
@@ -111,7 +130,64 @@ func (r *AddonReconciler) reportCatalogSourceUnreadinessStatus(
 	})
 	addon.Status.ObservedGeneration = addon.Generation
 	addon.Status.Phase = addonsv1alpha1.PhasePending
-	return r.Status().Update(ctx, addon)
+	return r.updateAddonStatus(ctx, addon)
+}
+
+// Container waiting reasons signaling that an image can not be pulled.
+var imagePullErrorReasons = map[string]struct{}{
+	"ErrImagePull":      {},
+	"ImagePullBackOff":  {},
+	"InvalidImageName":  {},
+	"ErrImageNeverPull": {},
+}
+
+// Label OLM puts on the registry Pods of a CatalogSource.
+const olmCatalogSourceLabel = "olm.catalogSource"
+
+// Returns a description of the image pull error of the registry Pods
+// of the given CatalogSource or an empty string, if there is none.
+func (r *AddonReconciler) getCatalogSourceImageError(
+	ctx context.Context, catalogSource *operatorsv1alpha1.CatalogSource) (string, error) {
+	pods := &corev1.PodList{}
+	if err := r.apiReader().List(ctx, pods,
+		client.InNamespace(catalogSource.Namespace),
+		client.MatchingLabels{
+			olmCatalogSourceLabel: catalogSource.Name,
+		}); err != nil {
+		return "", fmt.Errorf("could not list CatalogSource Pods: %w", err)
+	}
+
+	for _, pod := range pods.Items {
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			waiting := containerStatus.State.Waiting
+			if waiting == nil {
+				continue
+			}
+			if _, ok := imagePullErrorReasons[waiting.Reason]; ok {
+				return fmt.Sprintf("%s: %s", waiting.Reason, waiting.Message), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// Marks Addon as unavailable because the CatalogSource image can not be pulled
+func (r *AddonReconciler) reportCatalogImageError(
+	ctx context.Context,
+	addon *addonsv1alpha1.Addon,
+	message string) error {
+	meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+		Type:   addonsv1alpha1.Available,
+		Status: metav1.ConditionFalse,
+		Reason: addonsv1alpha1.AddonReasonCatalogImageError,
+		Message: fmt.Sprintf(
+			"CatalogSource image can not be pulled: %s",
+			message),
+		ObservedGeneration: addon.Generation,
+	})
+	addon.Status.ObservedGeneration = addon.Generation
+	addon.Status.Phase = addonsv1alpha1.PhasePending
+	return r.updateAddonStatus(ctx, addon)
 }
 
 // reconciles a CatalogSource and returns a new CatalogSource object with observed state.
@@ -133,8 +209,9 @@ func reconcileCatalogSource(ctx context.Context, c client.Client, catalogSource
 		}
 	}
 
-	// only update when spec has changed
-	if !equality.Semantic.DeepEqual(catalogSource.Spec, currentCatalogSource.Spec) {
+	labelsChanged := reconcileCommonLabels(currentCatalogSource, catalogSource)
+	// only update when spec or labels have changed
+	if labelsChanged || !equality.Semantic.DeepEqual(catalogSource.Spec, currentCatalogSource.Spec) {
 		// copy new spec into existing object and update in the k8s api
 		currentCatalogSource.Spec = catalogSource.Spec
 		return currentCatalogSource, c.Update(ctx, currentCatalogSource)
@@ -7,9 +7,14 @@ import (
 	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
 	"github.com/openshift/addon-operator/internal/testutil"
 )
 
@@ -175,3 +180,160 @@ func TestEnsureCatalogSource_Update(t *testing.T) {
 	c.AssertNumberOfCalls(t, "Get", 1)
 	c.AssertNumberOfCalls(t, "Update", 1)
 }
+
+func TestEnsureCatalogSource_AlreadyExistsOnCreate(t *testing.T) {
+	addon := newTestAddonWithCatalogSourceImage()
+
+	c := testutil.NewClient()
+	// cache has not observed the CatalogSource yet
+	c.On("Get",
+		mock.Anything,
+		testutil.IsObjectKey,
+		testutil.IsOperatorsV1Alpha1CatalogSourcePtr,
+	).Return(newTestErrNotFound()).Once()
+	c.On("Create",
+		mock.Anything,
+		testutil.IsOperatorsV1Alpha1CatalogSourcePtr,
+		mock.Anything,
+	).Return(k8sApiErrors.NewAlreadyExists(
+		operatorsv1alpha1.Resource("catalogsources"), addon.Name))
+	c.On("Get",
+		mock.Anything,
+		testutil.IsObjectKey,
+		testutil.IsOperatorsV1Alpha1CatalogSourcePtr,
+	).Run(func(args mock.Arguments) {
+		arg := args.Get(2).(*operatorsv1alpha1.CatalogSource)
+		arg.ResourceVersion = "123"
+		arg.Spec.Image = "quay.io/osd-addons/test:outdated"
+		arg.Status.GRPCConnectionState = &operatorsv1alpha1.GRPCConnectionState{
+			LastObservedState: "READY",
+		}
+	}).Return(nil)
+	var updatedCatalogSource *operatorsv1alpha1.CatalogSource
+	c.On("Update",
+		mock.Anything,
+		testutil.IsOperatorsV1Alpha1CatalogSourcePtr,
+		mock.Anything,
+	).Run(func(args mock.Arguments) {
+		updatedCatalogSource = args.Get(1).(*operatorsv1alpha1.CatalogSource)
+	}).Return(nil)
+
+	r := &AddonReconciler{
+		Client: c,
+		Log:    testutil.NewLogger(t),
+		Scheme: newTestSchemeWithAddonsv1alpha1(),
+	}
+
+	log := testutil.NewLogger(t)
+
+	ctx := context.Background()
+	ensureResult, _, err := r.ensureCatalogSource(ctx, log, addon)
+	require.NoError(t, err)
+	assert.Equal(t, ensureCatalogSourceResultNil, ensureResult)
+	c.AssertNumberOfCalls(t, "Create", 1)
+	if assert.NotNil(t, updatedCatalogSource) {
+		assert.Equal(t,
+			addon.Spec.Install.OLMOwnNamespace.CatalogSourceImage,
+			updatedCatalogSource.Spec.Image)
+		// update has to be done on the existing object
+		assert.Equal(t, "123", updatedCatalogSource.ResourceVersion)
+	}
+}
+
+func TestEnsureCatalogSource_ImageError(t *testing.T) {
+	tests := []struct {
+		name           string
+		waiting        *corev1.ContainerStateWaiting
+		expectedReason string
+	}{
+		{
+			name: "image pull error",
+			waiting: &corev1.ContainerStateWaiting{
+				Reason:  "ImagePullBackOff",
+				Message: "Back-off pulling image",
+			},
+			expectedReason: addonsv1alpha1.AddonReasonCatalogImageError,
+		},
+		{
+			name:           "healthy pull",
+			expectedReason: "UnreadyCatalogSource",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			addon := newTestAddonWithCatalogSourceImage()
+			// previously reported image error
+			meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+				Type:   addonsv1alpha1.Available,
+				Status: metav1.ConditionFalse,
+				Reason: addonsv1alpha1.AddonReasonCatalogImageError,
+			})
+
+			c := testutil.NewClient()
+			c.On("Get",
+				mock.Anything,
+				testutil.IsObjectKey,
+				testutil.IsOperatorsV1Alpha1CatalogSourcePtr,
+			).Run(func(args mock.Arguments) {
+				arg := args.Get(2).(*operatorsv1alpha1.CatalogSource)
+				arg.Name = addon.Name
+				arg.Namespace = "addon-1"
+				arg.Status.GRPCConnectionState = &operatorsv1alpha1.GRPCConnectionState{
+					LastObservedState: "TRANSIENT_FAILURE",
+				}
+			}).Return(nil)
+			c.On("Update",
+				mock.Anything,
+				testutil.IsOperatorsV1Alpha1CatalogSourcePtr,
+				mock.Anything,
+			).Return(nil)
+			// CatalogSource Pods are not cached, but read from the API server
+			apiReader := testutil.NewClient()
+			apiReader.On("List", mock.Anything, mock.IsType(&corev1.PodList{}), mock.Anything).
+				Run(func(args mock.Arguments) {
+					list := args.Get(1).(*corev1.PodList)
+					list.Items = []corev1.Pod{
+						{
+							Status: corev1.PodStatus{
+								ContainerStatuses: []corev1.ContainerStatus{
+									{
+										Name:  "registry-server",
+										State: corev1.ContainerState{Waiting: test.waiting},
+									},
+								},
+							},
+						},
+					}
+				}).
+				Return(nil)
+			c.StatusMock.
+				On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+				Return(nil)
+
+			r := &AddonReconciler{
+				Client:    c,
+				APIReader: apiReader,
+				Log:       testutil.NewLogger(t),
+				Scheme:    newTestSchemeWithAddonsv1alpha1(),
+			}
+
+			ctx := context.Background()
+			ensureResult, _, err := r.ensureCatalogSource(ctx, testutil.NewLogger(t), addon)
+			require.NoError(t, err)
+			assert.Equal(t, ensureCatalogSourceResultRetry, ensureResult)
+
+			c.AssertNotCalled(t, "List", mock.Anything, mock.Anything, mock.Anything)
+			apiReader.AssertCalled(t, "List", mock.Anything, mock.Anything,
+				[]client.ListOption{
+					client.InNamespace("addon-1"),
+					client.MatchingLabels{olmCatalogSourceLabel: addon.Name},
+				})
+			availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+			if assert.NotNil(t, availableCond) {
+				assert.Equal(t, metav1.ConditionFalse, availableCond.Status)
+				assert.Equal(t, test.expectedReason, availableCond.Reason)
+			}
+		})
+	}
+}
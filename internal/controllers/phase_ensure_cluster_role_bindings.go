@@ -0,0 +1,160 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+// Ensures ClusterRoleBindings for the ClusterRoles specified in the given Addon
+// and the cleanup of ClusterRoleBindings that are not needed anymore.
+// returns a bool that signals the caller to stop reconciliation
+func (r *AddonReconciler) ensureClusterRoleBindings(
+	ctx context.Context, addon *addonsv1alpha1.Addon) (stop bool, err error) {
+	targetNamespace := getCommonInstallOptions(addon).Namespace
+
+	desiredClusterRoleBindings := make([]*rbacv1.ClusterRoleBinding, 0, len(addon.Spec.ClusterRoleBindings))
+	for _, binding := range addon.Spec.ClusterRoleBindings {
+		desiredClusterRoleBinding := &rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   clusterRoleBindingName(addon, binding),
+				Labels: map[string]string{},
+			},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "ClusterRole",
+				Name:     binding.ClusterRoleName,
+			},
+			Subjects: []rbacv1.Subject{
+				{
+					Kind:      rbacv1.ServiceAccountKind,
+					Name:      binding.ServiceAccountName,
+					Namespace: targetNamespace,
+				},
+			},
+		}
+
+		addCommonLabels(desiredClusterRoleBinding.Labels, addon)
+		if err := controllerutil.SetControllerReference(addon, desiredClusterRoleBinding, r.Scheme); err != nil {
+			return false, fmt.Errorf("setting controller reference: %w", err)
+		}
+		desiredClusterRoleBindings = append(desiredClusterRoleBindings, desiredClusterRoleBinding)
+	}
+
+	// Check all ClusterRoleBindings before creating any of them,
+	// so we don't end up with a partially applied configuration.
+	for _, desiredClusterRoleBinding := range desiredClusterRoleBindings {
+		err := r.checkClusterRoleBindingAllowed(ctx, desiredClusterRoleBinding)
+		if k8sApiErrors.IsForbidden(err) {
+			// binding a ClusterRole the operator can't bind itself would be a privilege escalation
+			return true, r.reportConfigurationError(ctx, addon,
+				fmt.Sprintf(".spec.clusterRoleBindings: not allowed to bind ClusterRole %q: %s",
+					desiredClusterRoleBinding.RoleRef.Name, err))
+		}
+		if err != nil {
+			return false, fmt.Errorf(
+				"checking permission to bind ClusterRole %s: %w", desiredClusterRoleBinding.RoleRef.Name, err)
+		}
+	}
+
+	wantedClusterRoleBindingNames := map[string]struct{}{}
+	for _, desiredClusterRoleBinding := range desiredClusterRoleBindings {
+		if err := r.reconcileClusterRoleBinding(ctx, desiredClusterRoleBinding); err != nil {
+			return false, fmt.Errorf("reconciling ClusterRoleBinding: %w", err)
+		}
+		wantedClusterRoleBindingNames[desiredClusterRoleBinding.Name] = struct{}{}
+	}
+
+	// Ensure cleanup of ClusterRoleBindings that were previously created for this Addon
+	clusterRoleBindings := &rbacv1.ClusterRoleBindingList{}
+	if err := r.List(ctx, clusterRoleBindings,
+		client.MatchingLabelsSelector{
+			Selector: commonLabelsAsLabelSelector(addon),
+		}); err != nil {
+		return false, fmt.Errorf("could not list owned ClusterRoleBindings: %w", err)
+	}
+	for i := range clusterRoleBindings.Items {
+		clusterRoleBinding := &clusterRoleBindings.Items[i]
+		if _, ok := wantedClusterRoleBindingNames[clusterRoleBinding.Name]; ok {
+			continue
+		}
+
+		err := r.Delete(ctx, clusterRoleBinding)
+		// don't propagate error if the ClusterRoleBinding is already gone
+		if err != nil && !k8sApiErrors.IsNotFound(err) {
+			return false, fmt.Errorf("could not delete ClusterRoleBinding: %w", err)
+		}
+	}
+
+	return false, nil
+}
+
+// Reconciles the RoleRef and Subjects of the given ClusterRoleBinding
+// by creating, updating or replacing the ClusterRoleBinding if needed.
+func (r *AddonReconciler) reconcileClusterRoleBinding(
+	ctx context.Context, clusterRoleBinding *rbacv1.ClusterRoleBinding) error {
+	currentClusterRoleBinding := &rbacv1.ClusterRoleBinding{}
+
+	err := r.Get(ctx, client.ObjectKeyFromObject(clusterRoleBinding), currentClusterRoleBinding)
+	if k8sApiErrors.IsNotFound(err) {
+		return r.Create(ctx, clusterRoleBinding)
+	}
+	if err != nil {
+		return fmt.Errorf("getting ClusterRoleBinding: %w", err)
+	}
+
+	// roleRef is immutable, so the ClusterRoleBinding has to be replaced
+	if !equality.Semantic.DeepEqual(currentClusterRoleBinding.RoleRef, clusterRoleBinding.RoleRef) {
+		if err := r.Delete(ctx, currentClusterRoleBinding); err != nil &&
+			!k8sApiErrors.IsNotFound(err) {
+			return fmt.Errorf("deleting ClusterRoleBinding: %w", err)
+		}
+		return r.Create(ctx, clusterRoleBinding)
+	}
+
+	// only update when subjects have changed
+	if !equality.Semantic.DeepEqual(currentClusterRoleBinding.Subjects, clusterRoleBinding.Subjects) {
+		currentClusterRoleBinding.Subjects = clusterRoleBinding.Subjects
+		return r.Update(ctx, currentClusterRoleBinding)
+	}
+	return nil
+}
+
+// Checks whether the operator is allowed to create or change the given ClusterRoleBinding,
+// by creating it in dry-run mode.
+// The API server only allows binding ClusterRoles with permissions the operator holds itself,
+// or that it has been granted "bind" on, and responds with a Forbidden error otherwise.
+// ClusterRoleBindings that are already up to date have been checked when they were created.
+func (r *AddonReconciler) checkClusterRoleBindingAllowed(
+	ctx context.Context, clusterRoleBinding *rbacv1.ClusterRoleBinding) error {
+	currentClusterRoleBinding := &rbacv1.ClusterRoleBinding{}
+	err := r.Get(ctx, client.ObjectKeyFromObject(clusterRoleBinding), currentClusterRoleBinding)
+	if err != nil && !k8sApiErrors.IsNotFound(err) {
+		return fmt.Errorf("getting ClusterRoleBinding: %w", err)
+	}
+	if err == nil &&
+		equality.Semantic.DeepEqual(currentClusterRoleBinding.RoleRef, clusterRoleBinding.RoleRef) &&
+		equality.Semantic.DeepEqual(currentClusterRoleBinding.Subjects, clusterRoleBinding.Subjects) {
+		return nil
+	}
+
+	err = r.Create(ctx, clusterRoleBinding.DeepCopy(), client.DryRunAll)
+	if k8sApiErrors.IsAlreadyExists(err) {
+		// escalation checks happen before the existence check
+		return nil
+	}
+	return err
+}
+
+func clusterRoleBindingName(
+	addon *addonsv1alpha1.Addon, binding addonsv1alpha1.AddonClusterRoleBinding) string {
+	return fmt.Sprintf("%s-%s-%s", addon.Name, binding.ServiceAccountName, binding.ClusterRoleName)
+}
@@ -0,0 +1,280 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestEnsureClusterRoleBindings(t *testing.T) {
+	t.Run("creates ClusterRoleBinding", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithClusterRoleBinding()
+
+		c.On("Get", mock.Anything, client.ObjectKey{
+			Name: "addon-1-manager-cluster-reader",
+		}, mock.IsType(&rbacv1.ClusterRoleBinding{})).
+			Return(newTestErrNotFound())
+		c.On("Create", mock.Anything, mock.IsType(&rbacv1.ClusterRoleBinding{}),
+			[]client.CreateOption{client.DryRunAll}).
+			Return(nil)
+		var createdClusterRoleBinding *rbacv1.ClusterRoleBinding
+		c.On("Create", mock.Anything, mock.IsType(&rbacv1.ClusterRoleBinding{}),
+			[]client.CreateOption(nil)).
+			Run(func(args mock.Arguments) {
+				createdClusterRoleBinding = args.Get(1).(*rbacv1.ClusterRoleBinding)
+			}).
+			Return(nil)
+		c.On("List", mock.Anything, mock.IsType(&rbacv1.ClusterRoleBindingList{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				list := args.Get(1).(*rbacv1.ClusterRoleBindingList)
+				list.Items = []rbacv1.ClusterRoleBinding{*createdClusterRoleBinding}
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		stop, err := r.ensureClusterRoleBindings(ctx, addon)
+		require.NoError(t, err)
+		assert.False(t, stop)
+
+		c.AssertExpectations(t)
+		c.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+		if assert.NotNil(t, createdClusterRoleBinding) {
+			assert.Equal(t, "cluster-reader", createdClusterRoleBinding.RoleRef.Name)
+			assert.Equal(t, []rbacv1.Subject{
+				{
+					Kind:      rbacv1.ServiceAccountKind,
+					Name:      "manager",
+					Namespace: "addon-1",
+				},
+			}, createdClusterRoleBinding.Subjects)
+			assert.Equal(t, addon.Name, createdClusterRoleBinding.Labels[commonInstanceLabel])
+		}
+	})
+
+	t.Run("removes ClusterRoleBindings no longer specified", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithClusterRoleBinding()
+		addon.Spec.ClusterRoleBindings = nil
+
+		c.On("List", mock.Anything, mock.IsType(&rbacv1.ClusterRoleBindingList{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				list := args.Get(1).(*rbacv1.ClusterRoleBindingList)
+				list.Items = []rbacv1.ClusterRoleBinding{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "addon-1-manager-cluster-reader",
+						},
+					},
+				}
+			}).
+			Return(nil)
+		c.On("Delete", mock.Anything, mock.IsType(&rbacv1.ClusterRoleBinding{}), mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		stop, err := r.ensureClusterRoleBindings(ctx, addon)
+		require.NoError(t, err)
+		assert.False(t, stop)
+
+		c.AssertExpectations(t)
+		c.AssertCalled(t, "Delete", mock.Anything,
+			mock.MatchedBy(func(crb *rbacv1.ClusterRoleBinding) bool {
+				return crb.Name == "addon-1-manager-cluster-reader"
+			}), mock.Anything)
+		c.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects escalation", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithClusterRoleBinding()
+		addon.Spec.ClusterRoleBindings = append(addon.Spec.ClusterRoleBindings,
+			addonsv1alpha1.AddonClusterRoleBinding{
+				ClusterRoleName:    "cluster-admin",
+				ServiceAccountName: "manager",
+			})
+
+		c.On("Get", mock.Anything, mock.Anything, mock.IsType(&rbacv1.ClusterRoleBinding{})).
+			Return(newTestErrNotFound())
+		c.On("Create", mock.Anything,
+			mock.MatchedBy(func(crb *rbacv1.ClusterRoleBinding) bool {
+				return crb.RoleRef.Name != "cluster-admin"
+			}), []client.CreateOption{client.DryRunAll}).
+			Return(nil)
+		c.On("Create", mock.Anything,
+			mock.MatchedBy(func(crb *rbacv1.ClusterRoleBinding) bool {
+				return crb.RoleRef.Name == "cluster-admin"
+			}), []client.CreateOption{client.DryRunAll}).
+			Return(k8sApiErrors.NewForbidden(
+				rbacv1.Resource("clusterrolebindings"), "addon-1-manager-cluster-admin",
+				errors.New("user attempting to grant RBAC permissions not currently held")))
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		stop, err := r.ensureClusterRoleBindings(ctx, addon)
+		require.NoError(t, err)
+		assert.True(t, stop)
+
+		// nothing is bound, not even the allowed ClusterRole
+		c.AssertNotCalled(t, "Create", mock.Anything, mock.IsType(&rbacv1.ClusterRoleBinding{}),
+			[]client.CreateOption(nil))
+		c.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+		availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+		if assert.NotNil(t, availableCond) {
+			assert.Equal(t, metav1.ConditionFalse, availableCond.Status)
+			assert.Equal(t, "ConfigurationError", availableCond.Reason)
+			assert.Contains(t, availableCond.Message, `"cluster-admin"`)
+			assert.Contains(t, availableCond.Message, "not currently held")
+		}
+	})
+
+	t.Run("up to date ClusterRoleBinding is not checked again", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithClusterRoleBinding()
+
+		c.On("Get", mock.Anything, client.ObjectKey{
+			Name: "addon-1-manager-cluster-reader",
+		}, mock.IsType(&rbacv1.ClusterRoleBinding{})).
+			Run(func(args mock.Arguments) {
+				crb := args.Get(2).(*rbacv1.ClusterRoleBinding)
+				crb.Name = "addon-1-manager-cluster-reader"
+				crb.RoleRef = rbacv1.RoleRef{
+					APIGroup: rbacv1.GroupName,
+					Kind:     "ClusterRole",
+					Name:     "cluster-reader",
+				}
+				crb.Subjects = []rbacv1.Subject{
+					{
+						Kind:      rbacv1.ServiceAccountKind,
+						Name:      "manager",
+						Namespace: "addon-1",
+					},
+				}
+			}).
+			Return(nil)
+		c.On("List", mock.Anything, mock.IsType(&rbacv1.ClusterRoleBindingList{}), mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		stop, err := r.ensureClusterRoleBindings(ctx, addon)
+		require.NoError(t, err)
+		assert.False(t, stop)
+
+		c.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestReconcileClusterRoleBinding(t *testing.T) {
+	clusterRoleBinding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "addon-1-manager-cluster-reader",
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     "cluster-reader",
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      "manager",
+				Namespace: "addon-1",
+			},
+		},
+	}
+
+	t.Run("replaces ClusterRoleBinding on roleRef change", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		c.On("Get", mock.Anything, client.ObjectKeyFromObject(clusterRoleBinding),
+			mock.IsType(&rbacv1.ClusterRoleBinding{})).
+			Run(func(args mock.Arguments) {
+				crb := args.Get(2).(*rbacv1.ClusterRoleBinding)
+				clusterRoleBinding.DeepCopyInto(crb)
+				crb.RoleRef.Name = "view"
+			}).
+			Return(nil)
+		c.On("Delete", mock.Anything, mock.IsType(&rbacv1.ClusterRoleBinding{}), mock.Anything).
+			Return(nil)
+		c.On("Create", mock.Anything, mock.IsType(&rbacv1.ClusterRoleBinding{}), mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.reconcileClusterRoleBinding(ctx, clusterRoleBinding.DeepCopy())
+		require.NoError(t, err)
+
+		c.AssertExpectations(t)
+		c.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("no-op when up to date", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		c.On("Get", mock.Anything, client.ObjectKeyFromObject(clusterRoleBinding),
+			mock.IsType(&rbacv1.ClusterRoleBinding{})).
+			Run(func(args mock.Arguments) {
+				clusterRoleBinding.DeepCopyInto(args.Get(2).(*rbacv1.ClusterRoleBinding))
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.reconcileClusterRoleBinding(ctx, clusterRoleBinding.DeepCopy())
+		require.NoError(t, err)
+
+		c.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+		c.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func newTestAddonWithClusterRoleBinding() *addonsv1alpha1.Addon {
+	addon := newTestAddonWithCatalogSourceImage()
+	addon.Spec.ClusterRoleBindings = []addonsv1alpha1.AddonClusterRoleBinding{
+		{
+			ClusterRoleName:    "cluster-reader",
+			ServiceAccountName: "manager",
+		},
+	}
+	return addon
+}
@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+// Ensures the ConfigMaps specified in the given Addon resource
+// and the cleanup of ConfigMaps that are not needed anymore.
+// returns a bool that signals the caller to stop reconciliation
+func (r *AddonReconciler) ensureConfigMaps(
+	ctx context.Context, addon *addonsv1alpha1.Addon) (stop bool, err error) {
+	targetNamespace := getCommonInstallOptions(addon).Namespace
+
+	for _, configMap := range addon.Spec.ConfigMaps {
+		if err := validateConfigMap(configMap); err != nil {
+			// invalid configuration
+			return true, r.reportConfigurationError(ctx, addon,
+				fmt.Sprintf(".spec.configMaps[%s] is invalid: %s", configMap.Name, err))
+		}
+	}
+
+	wantedConfigMapNames := map[string]struct{}{}
+	for _, configMap := range addon.Spec.ConfigMaps {
+		desiredConfigMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      configMap.Name,
+				Namespace: targetNamespace,
+				Labels:    map[string]string{},
+			},
+			Data: configMap.Data,
+		}
+
+		addCommonLabels(desiredConfigMap.Labels, addon)
+		if err := controllerutil.SetControllerReference(addon, desiredConfigMap, r.Scheme); err != nil {
+			return false, fmt.Errorf("setting controller reference: %w", err)
+		}
+
+		if err := r.reconcileConfigMap(ctx, desiredConfigMap); err != nil {
+			return false, fmt.Errorf("reconciling ConfigMap: %w", err)
+		}
+		wantedConfigMapNames[desiredConfigMap.Name] = struct{}{}
+	}
+
+	// Ensure cleanup of ConfigMaps that were previously created for this Addon
+	configMaps := &corev1.ConfigMapList{}
+	if err := r.apiReader().List(ctx, configMaps,
+		client.InNamespace(targetNamespace),
+		client.MatchingLabelsSelector{
+			Selector: commonLabelsAsLabelSelector(addon),
+		}); err != nil {
+		return false, fmt.Errorf("could not list owned ConfigMaps: %w", err)
+	}
+	for i := range configMaps.Items {
+		configMap := &configMaps.Items[i]
+		if _, ok := wantedConfigMapNames[configMap.Name]; ok {
+			continue
+		}
+
+		err := r.Delete(ctx, configMap)
+		// don't propagate error if the ConfigMap is already gone
+		if err != nil && !k8sApiErrors.IsNotFound(err) {
+			return false, fmt.Errorf("could not delete ConfigMap: %w", err)
+		}
+	}
+
+	return false, nil
+}
+
+// Reconciles the managed keys of the given ConfigMap
+// by creating or updating the ConfigMap if needed.
+// Keys added by others are left untouched.
+func (r *AddonReconciler) reconcileConfigMap(
+	ctx context.Context, configMap *corev1.ConfigMap) error {
+	currentConfigMap := &corev1.ConfigMap{}
+
+	err := r.apiReader().Get(ctx, client.ObjectKeyFromObject(configMap), currentConfigMap)
+	if k8sApiErrors.IsNotFound(err) {
+		return r.Create(ctx, configMap)
+	}
+	if err != nil {
+		return fmt.Errorf("getting ConfigMap: %w", err)
+	}
+
+	var changed bool
+	for key, value := range configMap.Data {
+		if currentValue, ok := currentConfigMap.Data[key]; ok && currentValue == value {
+			continue
+		}
+
+		if currentConfigMap.Data == nil {
+			currentConfigMap.Data = map[string]string{}
+		}
+		currentConfigMap.Data[key] = value
+		changed = true
+	}
+	if changed {
+		return r.Update(ctx, currentConfigMap)
+	}
+	return nil
+}
+
+// Validates the name and keys of the given ConfigMap.
+func validateConfigMap(configMap addonsv1alpha1.AddonConfigMap) error {
+	if errs := validation.IsDNS1123Subdomain(configMap.Name); len(errs) > 0 {
+		return fmt.Errorf("invalid name %q: %s",
+			configMap.Name, strings.Join(errs, ", "))
+	}
+	for key := range configMap.Data {
+		if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+			return fmt.Errorf("invalid key %q: %s", key, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestEnsureConfigMaps(t *testing.T) {
+	t.Run("creates ConfigMap", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithConfigMap()
+
+		c.ExpectNotFound(client.ObjectKey{
+			Name:      "addon-config",
+			Namespace: "addon-1",
+		}, &corev1.ConfigMap{})
+		createdConfigMap := &corev1.ConfigMap{}
+		c.ExpectCreate(createdConfigMap)
+		c.ExpectList(&corev1.ConfigMapList{})
+
+		ctx := context.Background()
+		stop, err := r.ensureConfigMaps(ctx, addon)
+		require.NoError(t, err)
+		assert.False(t, stop)
+
+		c.AssertExpectations(t)
+		assert.Equal(t, map[string]string{"log-level": "info"}, createdConfigMap.Data)
+		assert.Equal(t, addon.Name, createdConfigMap.Labels[commonInstanceLabel])
+	})
+
+	t.Run("deletes ConfigMap removed from spec", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithConfigMap()
+		addon.Spec.ConfigMaps = nil
+
+		c.ExpectList(&corev1.ConfigMapList{}, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "addon-config",
+				Namespace: "addon-1",
+			},
+		})
+		c.ExpectDelete(&corev1.ConfigMap{})
+
+		ctx := context.Background()
+		stop, err := r.ensureConfigMaps(ctx, addon)
+		require.NoError(t, err)
+		assert.False(t, stop)
+
+		c.AssertExpectations(t)
+		c.AssertCalled(t, "Delete", mock.Anything,
+			mock.MatchedBy(func(cm *corev1.ConfigMap) bool {
+				return cm.Name == "addon-config"
+			}), mock.Anything)
+	})
+
+	t.Run("rejects invalid key", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithConfigMap()
+		addon.Spec.ConfigMaps[0].Data = map[string]string{"log level": "info"}
+
+		c.ExpectStatusUpdate()
+
+		ctx := context.Background()
+		stop, err := r.ensureConfigMaps(ctx, addon)
+		require.NoError(t, err)
+		assert.True(t, stop)
+
+		c.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+		availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+		if assert.NotNil(t, availableCond) {
+			assert.Equal(t, "ConfigurationError", availableCond.Reason)
+		}
+	})
+}
+
+func TestReconcileConfigMap(t *testing.T) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "addon-config",
+			Namespace: "addon-1",
+		},
+		Data: map[string]string{
+			"log-level": "info",
+		},
+	}
+
+	t.Run("corrects drift of managed keys", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		currentConfigMap := configMap.DeepCopy()
+		currentConfigMap.ResourceVersion = "123"
+		currentConfigMap.Data["log-level"] = "debug"
+		currentConfigMap.Data["added-by-someone"] = "else"
+		c.ExpectGet(currentConfigMap)
+		updatedConfigMap := &corev1.ConfigMap{}
+		c.ExpectUpdate(updatedConfigMap)
+
+		ctx := context.Background()
+		err := r.reconcileConfigMap(ctx, configMap.DeepCopy())
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]string{
+			"log-level":        "info",
+			"added-by-someone": "else",
+		}, updatedConfigMap.Data)
+		// update has to be done on the existing object
+		assert.Equal(t, "123", updatedConfigMap.ResourceVersion)
+	})
+
+	t.Run("no-op without drift of managed keys", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		currentConfigMap := configMap.DeepCopy()
+		currentConfigMap.Data["added-by-someone"] = "else"
+		c.ExpectGet(currentConfigMap)
+
+		ctx := context.Background()
+		err := r.reconcileConfigMap(ctx, configMap.DeepCopy())
+		require.NoError(t, err)
+		c.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func newTestAddonWithConfigMap() *addonsv1alpha1.Addon {
+	addon := testutil.NewAddon()
+	addon.Spec.ConfigMaps = []addonsv1alpha1.AddonConfigMap{
+		{
+			Name: "addon-config",
+			Data: map[string]string{"log-level": "info"},
+		},
+	}
+	return addon
+}
@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/robfig/cron/v3"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+// Ensures the CronJobs specified in the given Addon resource
+// and the cleanup of CronJobs that are not needed anymore.
+// returns a bool that signals the caller to stop reconciliation
+func (r *AddonReconciler) ensureCronJobs(
+	ctx context.Context, addon *addonsv1alpha1.Addon) (stop bool, err error) {
+	targetNamespace := getCommonInstallOptions(addon).Namespace
+
+	for _, cronJob := range addon.Spec.CronJobs {
+		if err := validateCronSchedule(cronJob.Schedule); err != nil {
+			// invalid configuration
+			return true, r.reportConfigurationError(ctx, addon,
+				fmt.Sprintf(".spec.cronJobs[%s].schedule is invalid: %s", cronJob.Name, err))
+		}
+	}
+
+	wantedCronJobNames := map[string]struct{}{}
+	for _, cronJob := range addon.Spec.CronJobs {
+		desiredCronJob := &batchv1beta1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cronJob.Name,
+				Namespace: targetNamespace,
+				Labels:    map[string]string{},
+			},
+			Spec: batchv1beta1.CronJobSpec{
+				Schedule: cronJob.Schedule,
+				// a periodic task that is still running doesn't need a second instance
+				ConcurrencyPolicy: batchv1beta1.ForbidConcurrent,
+				JobTemplate: batchv1beta1.JobTemplateSpec{
+					Spec: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								RestartPolicy: corev1.RestartPolicyOnFailure,
+								Containers: []corev1.Container{
+									{
+										Name:    cronJob.Name,
+										Image:   cronJob.Image,
+										Command: cronJob.Command,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		addCommonLabels(desiredCronJob.Labels, addon)
+		if err := controllerutil.SetControllerReference(addon, desiredCronJob, r.Scheme); err != nil {
+			return false, fmt.Errorf("setting controller reference: %w", err)
+		}
+
+		if err := r.reconcileCronJob(ctx, desiredCronJob); err != nil {
+			return false, fmt.Errorf("reconciling CronJob: %w", err)
+		}
+		wantedCronJobNames[desiredCronJob.Name] = struct{}{}
+	}
+
+	// Ensure cleanup of CronJobs that were previously created for this Addon
+	cronJobs := &batchv1beta1.CronJobList{}
+	if err := r.List(ctx, cronJobs,
+		client.InNamespace(targetNamespace),
+		client.MatchingLabelsSelector{
+			Selector: commonLabelsAsLabelSelector(addon),
+		}); err != nil {
+		return false, fmt.Errorf("could not list owned CronJobs: %w", err)
+	}
+	for i := range cronJobs.Items {
+		cronJob := &cronJobs.Items[i]
+		if _, ok := wantedCronJobNames[cronJob.Name]; ok {
+			continue
+		}
+
+		// also remove Jobs and Pods created by the CronJob
+		err := r.Delete(ctx, cronJob, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		// don't propagate error if the CronJob is already gone
+		if err != nil && !k8sApiErrors.IsNotFound(err) {
+			return false, fmt.Errorf("could not delete CronJob: %w", err)
+		}
+	}
+
+	return false, nil
+}
+
+// Reconciles the schedule and job template of the given CronJob
+// by creating or updating the CronJob if needed.
+func (r *AddonReconciler) reconcileCronJob(
+	ctx context.Context, cronJob *batchv1beta1.CronJob) error {
+	currentCronJob := &batchv1beta1.CronJob{}
+
+	err := r.Get(ctx, client.ObjectKeyFromObject(cronJob), currentCronJob)
+	if k8sApiErrors.IsNotFound(err) {
+		return r.Create(ctx, cronJob)
+	}
+	if err != nil {
+		return fmt.Errorf("getting CronJob: %w", err)
+	}
+
+	// The job template is heavily defaulted by the kube-apiserver,
+	// so only the fields we care about are compared.
+	if currentCronJob.Spec.Schedule != cronJob.Spec.Schedule ||
+		currentCronJob.Spec.ConcurrencyPolicy != cronJob.Spec.ConcurrencyPolicy ||
+		!equalCronJobContainers(
+			currentCronJob.Spec.JobTemplate.Spec.Template.Spec.Containers,
+			cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers) {
+		currentCronJob.Spec.Schedule = cronJob.Spec.Schedule
+		currentCronJob.Spec.ConcurrencyPolicy = cronJob.Spec.ConcurrencyPolicy
+		currentCronJob.Spec.JobTemplate = cronJob.Spec.JobTemplate
+		return r.Update(ctx, currentCronJob)
+	}
+	return nil
+}
+
+// Compares name, image and command of the given containers.
+func equalCronJobContainers(current, desired []corev1.Container) bool {
+	if len(current) != len(desired) {
+		return false
+	}
+	for i := range desired {
+		if current[i].Name != desired[i].Name ||
+			current[i].Image != desired[i].Image ||
+			!equality.Semantic.DeepEqual(current[i].Command, desired[i].Command) {
+			return false
+		}
+	}
+	return true
+}
+
+// Validates the given schedule in Cron format,
+// with the same parser the CronJob controller uses.
+func validateCronSchedule(schedule string) error {
+	_, err := cron.ParseStandard(schedule)
+	return err
+}
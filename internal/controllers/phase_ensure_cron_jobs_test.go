@@ -0,0 +1,255 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestEnsureCronJobs(t *testing.T) {
+	t.Run("creates CronJob", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithCronJob()
+
+		c.On("Get", mock.Anything, client.ObjectKey{
+			Name:      "cert-check",
+			Namespace: "addon-1",
+		}, mock.IsType(&batchv1beta1.CronJob{})).
+			Return(newTestErrNotFound())
+		var createdCronJob *batchv1beta1.CronJob
+		c.On("Create", mock.Anything, mock.IsType(&batchv1beta1.CronJob{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				createdCronJob = args.Get(1).(*batchv1beta1.CronJob)
+			}).
+			Return(nil)
+		c.On("List", mock.Anything, mock.IsType(&batchv1beta1.CronJobList{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				list := args.Get(1).(*batchv1beta1.CronJobList)
+				list.Items = []batchv1beta1.CronJob{*createdCronJob}
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		stop, err := r.ensureCronJobs(ctx, addon)
+		require.NoError(t, err)
+		assert.False(t, stop)
+
+		c.AssertExpectations(t)
+		c.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+		if assert.NotNil(t, createdCronJob) {
+			assert.Equal(t, "0 */6 * * *", createdCronJob.Spec.Schedule)
+			assert.Equal(t, addon.Name, createdCronJob.Labels[commonInstanceLabel])
+			containers := createdCronJob.Spec.JobTemplate.Spec.Template.Spec.Containers
+			if assert.Len(t, containers, 1) {
+				assert.Equal(t, "quay.io/osd-addons/cert-check:v1", containers[0].Image)
+				assert.Equal(t, []string{"/check"}, containers[0].Command)
+			}
+		}
+	})
+
+	t.Run("deletes CronJob removed from spec", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithCronJob()
+		addon.Spec.CronJobs = nil
+
+		c.On("List", mock.Anything, mock.IsType(&batchv1beta1.CronJobList{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				list := args.Get(1).(*batchv1beta1.CronJobList)
+				list.Items = []batchv1beta1.CronJob{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "cert-check",
+							Namespace: "addon-1",
+						},
+					},
+				}
+			}).
+			Return(nil)
+		c.On("Delete", mock.Anything, mock.IsType(&batchv1beta1.CronJob{}), mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		stop, err := r.ensureCronJobs(ctx, addon)
+		require.NoError(t, err)
+		assert.False(t, stop)
+
+		c.AssertExpectations(t)
+		c.AssertCalled(t, "Delete", mock.Anything,
+			mock.MatchedBy(func(cj *batchv1beta1.CronJob) bool {
+				return cj.Name == "cert-check" && cj.Namespace == "addon-1"
+			}), mock.Anything)
+	})
+
+	t.Run("rejects invalid schedule", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithCronJob()
+		addon.Spec.CronJobs[0].Schedule = "0 25 * * *"
+
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		stop, err := r.ensureCronJobs(ctx, addon)
+		require.NoError(t, err)
+		assert.True(t, stop)
+
+		c.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+		c.StatusMock.AssertCalled(t, "Update", mock.Anything, addon, mock.Anything)
+		availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+		if assert.NotNil(t, availableCond) {
+			assert.Equal(t, metav1.ConditionFalse, availableCond.Status)
+			assert.Equal(t, "ConfigurationError", availableCond.Reason)
+			assert.Contains(t, availableCond.Message, "cert-check")
+		}
+	})
+}
+
+func TestReconcileCronJob(t *testing.T) {
+	cronJob := &batchv1beta1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cert-check",
+			Namespace: "addon-1",
+		},
+		Spec: batchv1beta1.CronJobSpec{
+			Schedule:          "0 */6 * * *",
+			ConcurrencyPolicy: batchv1beta1.ForbidConcurrent,
+		},
+	}
+	cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers = []corev1.Container{
+		{
+			Name:  "cert-check",
+			Image: "quay.io/osd-addons/cert-check:v1",
+		},
+	}
+
+	t.Run("no-op with server defaults", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		c.On("Get", mock.Anything, client.ObjectKeyFromObject(cronJob),
+			mock.IsType(&batchv1beta1.CronJob{})).
+			Run(func(args mock.Arguments) {
+				cj := args.Get(2).(*batchv1beta1.CronJob)
+				cronJob.DeepCopyInto(cj)
+				cj.Spec.JobTemplate.Spec.Template.Spec.Containers[0].ImagePullPolicy = corev1.PullIfNotPresent
+				cj.Spec.JobTemplate.Spec.Template.Spec.DNSPolicy = corev1.DNSClusterFirst
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.reconcileCronJob(ctx, cronJob.DeepCopy())
+		require.NoError(t, err)
+		c.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("corrects schedule and image drift", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		c.On("Get", mock.Anything, client.ObjectKeyFromObject(cronJob),
+			mock.IsType(&batchv1beta1.CronJob{})).
+			Run(func(args mock.Arguments) {
+				cj := args.Get(2).(*batchv1beta1.CronJob)
+				cronJob.DeepCopyInto(cj)
+				cj.ResourceVersion = "123"
+				cj.Spec.Schedule = "* * * * *"
+				cj.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Image = "quay.io/somebody/else:v1"
+			}).
+			Return(nil)
+		var updatedCronJob *batchv1beta1.CronJob
+		c.On("Update", mock.Anything, mock.IsType(&batchv1beta1.CronJob{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				updatedCronJob = args.Get(1).(*batchv1beta1.CronJob)
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.reconcileCronJob(ctx, cronJob.DeepCopy())
+		require.NoError(t, err)
+
+		if assert.NotNil(t, updatedCronJob) {
+			assert.Equal(t, cronJob.Spec, updatedCronJob.Spec)
+			// update has to be done on the existing object
+			assert.Equal(t, "123", updatedCronJob.ResourceVersion)
+		}
+	})
+}
+
+func TestValidateCronSchedule(t *testing.T) {
+	for _, schedule := range []string{
+		"* * * * *",
+		"0 */6 * * *",
+		"15,45 8-18 * JAN-jun mon-FRI",
+		"0 0 1 */2 ?",
+		"@daily",
+		"@hourly",
+		"@every 1h30m",
+		"TZ=Europe/Berlin 0 6 * * *",
+	} {
+		assert.NoError(t, validateCronSchedule(schedule), schedule)
+	}
+
+	for _, schedule := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"0 0 0 * *",
+		"0 0 * 13 *",
+		"0 0 * * 7",
+		"0 18-8 * * *",
+		"*/0 * * * *",
+		"0 0 * FOO *",
+		"@fortnightly",
+		"@every soon",
+		"TZ=Nowhere/Special 0 6 * * *",
+	} {
+		assert.Error(t, validateCronSchedule(schedule), schedule)
+	}
+}
+
+func newTestAddonWithCronJob() *addonsv1alpha1.Addon {
+	addon := newTestAddonWithCatalogSourceImage()
+	addon.Spec.CronJobs = []addonsv1alpha1.AddonCronJob{
+		{
+			Name:     "cert-check",
+			Schedule: "0 */6 * * *",
+			Image:    "quay.io/osd-addons/cert-check:v1",
+			Command:  []string{"/check"},
+		},
+	}
+	return addon
+}
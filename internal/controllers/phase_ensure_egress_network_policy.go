@@ -0,0 +1,168 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+// The OpenShift network API is not vendored,
+// so EgressNetworkPolicies are handled as unstructured objects.
+var egressNetworkPolicyGVK = schema.GroupVersionKind{
+	Group:   "network.openshift.io",
+	Version: "v1",
+	Kind:    "EgressNetworkPolicy",
+}
+
+// Ensures the EgressNetworkPolicy specified in the given Addon resource
+// and the cleanup of EgressNetworkPolicies that are not needed anymore.
+// returns a bool that signals the caller to stop reconciliation
+func (r *AddonReconciler) ensureEgressNetworkPolicy(
+	ctx context.Context, addon *addonsv1alpha1.Addon) (stop bool, err error) {
+	targetNamespace := getCommonInstallOptions(addon).Namespace
+
+	var wantedPolicyName string
+	if addon.Spec.Egress != nil {
+		if err := validateCIDRs(addon.Spec.Egress.AllowedCIDRs); err != nil {
+			// invalid configuration
+			return true, r.reportConfigurationError(ctx, addon,
+				fmt.Sprintf(".spec.egress.allowedCIDRs is invalid: %s", err))
+		}
+
+		desiredPolicy := newEgressNetworkPolicy(
+			addon.Name, targetNamespace, addon.Spec.Egress.AllowedCIDRs)
+		labels := map[string]string{}
+		addCommonLabels(labels, addon)
+		desiredPolicy.SetLabels(labels)
+		if err := controllerutil.SetControllerReference(addon, desiredPolicy, r.Scheme); err != nil {
+			return false, fmt.Errorf("setting controller reference: %w", err)
+		}
+
+		err := r.reconcileEgressNetworkPolicy(ctx, desiredPolicy)
+		if meta.IsNoMatchError(err) {
+			// not an OpenShift cluster
+			return true, r.reportConfigurationError(ctx, addon,
+				".spec.egress requires EgressNetworkPolicies, which are not available on this cluster")
+		}
+		if err != nil {
+			return false, fmt.Errorf("reconciling EgressNetworkPolicy: %w", err)
+		}
+		wantedPolicyName = desiredPolicy.GetName()
+	}
+
+	// Ensure cleanup of EgressNetworkPolicies that were previously created for this Addon
+	policies := &unstructured.UnstructuredList{}
+	policies.SetGroupVersionKind(egressNetworkPolicyGVK.GroupVersion().WithKind(
+		egressNetworkPolicyGVK.Kind + "List"))
+	err = r.List(ctx, policies,
+		client.InNamespace(targetNamespace),
+		client.MatchingLabelsSelector{
+			Selector: commonLabelsAsLabelSelector(addon),
+		})
+	if meta.IsNoMatchError(err) {
+		// not an OpenShift cluster, so there is nothing to clean up
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("could not list owned EgressNetworkPolicies: %w", err)
+	}
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		if policy.GetName() == wantedPolicyName {
+			continue
+		}
+
+		err := r.Delete(ctx, policy)
+		// don't propagate error if the EgressNetworkPolicy is already gone
+		if err != nil && !k8sApiErrors.IsNotFound(err) {
+			return false, fmt.Errorf("could not delete EgressNetworkPolicy: %w", err)
+		}
+	}
+
+	return false, nil
+}
+
+// Reconciles the Spec of the given EgressNetworkPolicy if needed
+// by updating or creating the EgressNetworkPolicy.
+func (r *AddonReconciler) reconcileEgressNetworkPolicy(
+	ctx context.Context, policy *unstructured.Unstructured) error {
+	currentPolicy := &unstructured.Unstructured{}
+	currentPolicy.SetGroupVersionKind(egressNetworkPolicyGVK)
+
+	err := r.Get(ctx, client.ObjectKeyFromObject(policy), currentPolicy)
+	if k8sApiErrors.IsNotFound(err) {
+		return r.Create(ctx, policy)
+	}
+	if meta.IsNoMatchError(err) {
+		// not wrapped, so the caller can detect the missing API
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("getting EgressNetworkPolicy: %w", err)
+	}
+
+	// only update when spec has changed
+	if !equality.Semantic.DeepEqual(currentPolicy.Object["spec"], policy.Object["spec"]) {
+		currentPolicy.Object["spec"] = policy.Object["spec"]
+		return r.Update(ctx, currentPolicy)
+	}
+	return nil
+}
+
+// Builds an EgressNetworkPolicy allowing egress to the given CIDRs and denying everything else.
+func newEgressNetworkPolicy(
+	name, namespace string, allowedCIDRs []string) *unstructured.Unstructured {
+	// rules are evaluated in order, the first matching rule applies
+	egress := make([]interface{}, 0, len(allowedCIDRs)+1)
+	for _, cidr := range allowedCIDRs {
+		egress = append(egress, map[string]interface{}{
+			"type": "Allow",
+			"to": map[string]interface{}{
+				"cidrSelector": cidr,
+			},
+		})
+	}
+	egress = append(egress, map[string]interface{}{
+		"type": "Deny",
+		"to": map[string]interface{}{
+			"cidrSelector": "0.0.0.0/0",
+		},
+	})
+
+	policy := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"egress": egress,
+			},
+		},
+	}
+	policy.SetGroupVersionKind(egressNetworkPolicyGVK)
+	policy.SetName(name)
+	policy.SetNamespace(namespace)
+	return policy
+}
+
+// Checks that all given strings are valid IPv4 CIDR notations.
+// EgressNetworkPolicies only deny IPv4 traffic, so allowing IPv6 CIDRs would be misleading.
+func validateCIDRs(cidrs []string) error {
+	for _, cidr := range cidrs {
+		ip, _, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return err
+		}
+		if ip.To4() == nil {
+			return fmt.Errorf("%s is not an IPv4 CIDR", cidr)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestEnsureEgressNetworkPolicy(t *testing.T) {
+	t.Run("creates EgressNetworkPolicy", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithEgress()
+
+		c.On("Get", mock.Anything, client.ObjectKey{
+			Name:      addon.Name,
+			Namespace: "addon-1",
+		}, mock.IsType(&unstructured.Unstructured{})).
+			Return(newTestErrNotFound())
+		var createdPolicy *unstructured.Unstructured
+		c.On("Create", mock.Anything, mock.IsType(&unstructured.Unstructured{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				createdPolicy = args.Get(1).(*unstructured.Unstructured)
+			}).
+			Return(nil)
+		c.On("List", mock.Anything, mock.IsType(&unstructured.UnstructuredList{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				list := args.Get(1).(*unstructured.UnstructuredList)
+				list.Items = []unstructured.Unstructured{*createdPolicy}
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		stop, err := r.ensureEgressNetworkPolicy(ctx, addon)
+		require.NoError(t, err)
+		assert.False(t, stop)
+
+		c.AssertExpectations(t)
+		c.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+		if assert.NotNil(t, createdPolicy) {
+			assert.Equal(t, egressNetworkPolicyGVK, createdPolicy.GroupVersionKind())
+			assert.Equal(t, addon.Name, createdPolicy.GetLabels()[commonInstanceLabel])
+
+			egress, _, _ := unstructured.NestedSlice(createdPolicy.Object, "spec", "egress")
+			assert.Equal(t, []interface{}{
+				map[string]interface{}{
+					"type": "Allow",
+					"to":   map[string]interface{}{"cidrSelector": "10.0.0.0/16"},
+				},
+				map[string]interface{}{
+					"type": "Deny",
+					"to":   map[string]interface{}{"cidrSelector": "0.0.0.0/0"},
+				},
+			}, egress)
+		}
+	})
+
+	t.Run("deletes EgressNetworkPolicy removed from spec", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithEgress()
+		addon.Spec.Egress = nil
+
+		c.On("List", mock.Anything, mock.IsType(&unstructured.UnstructuredList{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				list := args.Get(1).(*unstructured.UnstructuredList)
+				list.Items = []unstructured.Unstructured{
+					*newEgressNetworkPolicy(addon.Name, "addon-1", nil),
+				}
+			}).
+			Return(nil)
+		c.On("Delete", mock.Anything, mock.IsType(&unstructured.Unstructured{}), mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		stop, err := r.ensureEgressNetworkPolicy(ctx, addon)
+		require.NoError(t, err)
+		assert.False(t, stop)
+
+		c.AssertExpectations(t)
+		c.AssertCalled(t, "Delete", mock.Anything,
+			mock.MatchedBy(func(policy *unstructured.Unstructured) bool {
+				return policy.GetName() == addon.Name && policy.GetNamespace() == "addon-1"
+			}), mock.Anything)
+	})
+
+	t.Run("tolerates missing API", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithEgress()
+		addon.Spec.Egress = nil
+
+		c.On("List", mock.Anything, mock.IsType(&unstructured.UnstructuredList{}), mock.Anything).
+			Return(&meta.NoKindMatchError{
+				GroupKind: egressNetworkPolicyGVK.GroupKind(),
+			})
+
+		ctx := context.Background()
+		stop, err := r.ensureEgressNetworkPolicy(ctx, addon)
+		require.NoError(t, err)
+		assert.False(t, stop)
+	})
+
+	t.Run("reports missing API", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithEgress()
+
+		c.On("Get", mock.Anything, mock.Anything, mock.IsType(&unstructured.Unstructured{})).
+			Return(&meta.NoKindMatchError{
+				GroupKind: egressNetworkPolicyGVK.GroupKind(),
+			})
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		stop, err := r.ensureEgressNetworkPolicy(ctx, addon)
+		require.NoError(t, err)
+		assert.True(t, stop)
+
+		availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+		if assert.NotNil(t, availableCond) {
+			assert.Equal(t, metav1.ConditionFalse, availableCond.Status)
+			assert.Equal(t, "ConfigurationError", availableCond.Reason)
+		}
+	})
+
+	t.Run("rejects IPv6 CIDR", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithEgress()
+		addon.Spec.Egress.AllowedCIDRs = append(addon.Spec.Egress.AllowedCIDRs, "fd00::/8")
+
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		stop, err := r.ensureEgressNetworkPolicy(ctx, addon)
+		require.NoError(t, err)
+		assert.True(t, stop)
+
+		c.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+		availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+		if assert.NotNil(t, availableCond) {
+			assert.Equal(t, "ConfigurationError", availableCond.Reason)
+			assert.Contains(t, availableCond.Message, "fd00::/8")
+		}
+	})
+
+	t.Run("rejects invalid CIDR", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithEgress()
+		addon.Spec.Egress.AllowedCIDRs = append(addon.Spec.Egress.AllowedCIDRs, "10.0.0.300/8")
+
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		stop, err := r.ensureEgressNetworkPolicy(ctx, addon)
+		require.NoError(t, err)
+		assert.True(t, stop)
+
+		c.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+		c.StatusMock.AssertCalled(t, "Update", mock.Anything, addon, mock.Anything)
+		availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+		if assert.NotNil(t, availableCond) {
+			assert.Equal(t, metav1.ConditionFalse, availableCond.Status)
+			assert.Equal(t, "ConfigurationError", availableCond.Reason)
+			assert.Contains(t, availableCond.Message, "10.0.0.300/8")
+		}
+	})
+}
+
+func TestReconcileEgressNetworkPolicy(t *testing.T) {
+	policy := newEgressNetworkPolicy("addon-1", "addon-1", []string{"10.0.0.0/16"})
+
+	t.Run("no-op", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		c.On("Get", mock.Anything, client.ObjectKeyFromObject(policy),
+			mock.IsType(&unstructured.Unstructured{})).
+			Run(func(args mock.Arguments) {
+				policy.DeepCopyInto(args.Get(2).(*unstructured.Unstructured))
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.reconcileEgressNetworkPolicy(ctx, policy.DeepCopy())
+		require.NoError(t, err)
+		c.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("corrects drift", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		c.On("Get", mock.Anything, client.ObjectKeyFromObject(policy),
+			mock.IsType(&unstructured.Unstructured{})).
+			Run(func(args mock.Arguments) {
+				current := args.Get(2).(*unstructured.Unstructured)
+				newEgressNetworkPolicy("addon-1", "addon-1", []string{"0.0.0.0/0"}).
+					DeepCopyInto(current)
+				current.SetResourceVersion("123")
+			}).
+			Return(nil)
+		var updatedPolicy *unstructured.Unstructured
+		c.On("Update", mock.Anything, mock.IsType(&unstructured.Unstructured{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				updatedPolicy = args.Get(1).(*unstructured.Unstructured)
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.reconcileEgressNetworkPolicy(ctx, policy.DeepCopy())
+		require.NoError(t, err)
+
+		if assert.NotNil(t, updatedPolicy) {
+			assert.Equal(t, policy.Object["spec"], updatedPolicy.Object["spec"])
+			// update has to be done on the existing object
+			assert.Equal(t, "123", updatedPolicy.GetResourceVersion())
+		}
+	})
+}
+
+func newTestAddonWithEgress() *addonsv1alpha1.Addon {
+	addon := newTestAddonWithCatalogSourceImage()
+	addon.Spec.Egress = &addonsv1alpha1.AddonEgress{
+		AllowedCIDRs: []string{"10.0.0.0/16"},
+	}
+	return addon
+}
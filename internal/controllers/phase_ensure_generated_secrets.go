@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+// Number of random bytes generated for every key of a generated Secret.
+const generatedSecretValueBytes = 32
+
+// Ensures the generated Secrets specified in the given Addon resource
+// and the cleanup of generated Secrets that are not needed anymore.
+// returns a bool that signals the caller to stop reconciliation
+func (r *AddonReconciler) ensureGeneratedSecrets(
+	ctx context.Context, addon *addonsv1alpha1.Addon) (stop bool, err error) {
+	targetNamespace := getCommonInstallOptions(addon).Namespace
+
+	for _, secret := range addon.Spec.GeneratedSecrets {
+		if err := validateGeneratedSecret(secret); err != nil {
+			// invalid configuration
+			return true, r.reportConfigurationError(ctx, addon,
+				fmt.Sprintf(".spec.generatedSecrets[%s] is invalid: %s", secret.Name, err))
+		}
+	}
+
+	wantedSecretNames := map[string]struct{}{}
+	for _, secret := range addon.Spec.GeneratedSecrets {
+		key := client.ObjectKey{
+			Name:      secret.Name,
+			Namespace: targetNamespace,
+		}
+		wantedSecretNames[key.Name] = struct{}{}
+
+		// generated values must never be overwritten,
+		// so an existing Secret is left untouched
+		err := r.apiReader().Get(ctx, key, &corev1.Secret{})
+		if err == nil {
+			continue
+		}
+		if !k8sApiErrors.IsNotFound(err) {
+			return false, fmt.Errorf("getting Secret: %w", err)
+		}
+
+		desiredSecret, err := newGeneratedSecret(key, secret.Keys)
+		if err != nil {
+			return false, fmt.Errorf("generating Secret: %w", err)
+		}
+		addCommonLabels(desiredSecret.Labels, addon)
+		if err := controllerutil.SetControllerReference(addon, desiredSecret, r.Scheme); err != nil {
+			return false, fmt.Errorf("setting controller reference: %w", err)
+		}
+
+		if err := r.Create(ctx, desiredSecret); err != nil {
+			return false, fmt.Errorf("creating Secret: %w", err)
+		}
+	}
+
+	// Ensure cleanup of Secrets that were previously generated for this Addon
+	secrets := &corev1.SecretList{}
+	if err := r.apiReader().List(ctx, secrets,
+		client.InNamespace(targetNamespace),
+		client.MatchingLabelsSelector{
+			Selector: commonLabelsAsLabelSelector(addon),
+		}); err != nil {
+		return false, fmt.Errorf("could not list owned Secrets: %w", err)
+	}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if _, ok := wantedSecretNames[secret.Name]; ok {
+			continue
+		}
+
+		err := r.Delete(ctx, secret)
+		// don't propagate error if the Secret is already gone
+		if err != nil && !k8sApiErrors.IsNotFound(err) {
+			return false, fmt.Errorf("could not delete Secret: %w", err)
+		}
+	}
+
+	return false, nil
+}
+
+// Returns a new Secret with a random value for every given key.
+func newGeneratedSecret(key client.ObjectKey, keys []string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      key.Name,
+			Namespace: key.Namespace,
+			Labels:    map[string]string{},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{},
+	}
+	for _, k := range keys {
+		value := make([]byte, generatedSecretValueBytes)
+		if _, err := rand.Read(value); err != nil {
+			return nil, fmt.Errorf("reading random bytes: %w", err)
+		}
+		secret.Data[k] = []byte(base64.RawURLEncoding.EncodeToString(value))
+	}
+	return secret, nil
+}
+
+// Validates the name and keys of the given generated Secret.
+func validateGeneratedSecret(secret addonsv1alpha1.AddonGeneratedSecret) error {
+	if errs := validation.IsDNS1123Subdomain(secret.Name); len(errs) > 0 {
+		return fmt.Errorf("invalid name %q: %s",
+			secret.Name, strings.Join(errs, ", "))
+	}
+	for _, key := range secret.Keys {
+		if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+			return fmt.Errorf("invalid key %q: %s", key, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestEnsureGeneratedSecrets(t *testing.T) {
+	secretKey := client.ObjectKey{
+		Name:      "addon-token",
+		Namespace: "addon-1",
+	}
+
+	t.Run("generates Secret", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithGeneratedSecret()
+
+		c.On("Get", mock.Anything, secretKey, mock.IsType(&corev1.Secret{})).
+			Return(newTestErrNotFound())
+		var createdSecret *corev1.Secret
+		c.On("Create", mock.Anything, mock.IsType(&corev1.Secret{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				createdSecret = args.Get(1).(*corev1.Secret)
+			}).
+			Return(nil)
+		c.On("List", mock.Anything, mock.IsType(&corev1.SecretList{}), mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		stop, err := r.ensureGeneratedSecrets(ctx, addon)
+		require.NoError(t, err)
+		assert.False(t, stop)
+
+		c.AssertExpectations(t)
+		if assert.NotNil(t, createdSecret) {
+			assert.Len(t, createdSecret.Data, 2)
+			assert.NotEmpty(t, createdSecret.Data["token"])
+			assert.NotEqual(t, createdSecret.Data["token"], createdSecret.Data["session-key"])
+			assert.Equal(t, addon.Name, createdSecret.Labels[commonInstanceLabel])
+		}
+	})
+
+	t.Run("preserves existing Secret", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithGeneratedSecret()
+
+		c.On("Get", mock.Anything, secretKey, mock.IsType(&corev1.Secret{})).
+			Run(func(args mock.Arguments) {
+				secret := args.Get(2).(*corev1.Secret)
+				secret.Name = secretKey.Name
+				secret.Namespace = secretKey.Namespace
+				secret.Data = map[string][]byte{"token": []byte("generated-before")}
+			}).
+			Return(nil)
+		c.On("List", mock.Anything, mock.IsType(&corev1.SecretList{}), mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		stop, err := r.ensureGeneratedSecrets(ctx, addon)
+		require.NoError(t, err)
+		assert.False(t, stop)
+
+		c.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+		c.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+		c.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("deletes Secret removed from spec", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithGeneratedSecret()
+		addon.Spec.GeneratedSecrets = nil
+
+		c.On("List", mock.Anything, mock.IsType(&corev1.SecretList{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				list := args.Get(1).(*corev1.SecretList)
+				list.Items = []corev1.Secret{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      secretKey.Name,
+							Namespace: secretKey.Namespace,
+						},
+					},
+				}
+			}).
+			Return(nil)
+		c.On("Delete", mock.Anything, mock.IsType(&corev1.Secret{}), mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		stop, err := r.ensureGeneratedSecrets(ctx, addon)
+		require.NoError(t, err)
+		assert.False(t, stop)
+
+		c.AssertExpectations(t)
+		c.AssertCalled(t, "Delete", mock.Anything,
+			mock.MatchedBy(func(secret *corev1.Secret) bool {
+				return secret.Name == secretKey.Name
+			}), mock.Anything)
+	})
+
+	t.Run("rejects invalid key", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithGeneratedSecret()
+		addon.Spec.GeneratedSecrets[0].Keys = []string{"session key"}
+
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		stop, err := r.ensureGeneratedSecrets(ctx, addon)
+		require.NoError(t, err)
+		assert.True(t, stop)
+
+		c.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+		availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+		if assert.NotNil(t, availableCond) {
+			assert.Equal(t, "ConfigurationError", availableCond.Reason)
+		}
+	})
+}
+
+func newTestAddonWithGeneratedSecret() *addonsv1alpha1.Addon {
+	addon := newTestAddonWithCatalogSourceImage()
+	addon.Spec.GeneratedSecrets = []addonsv1alpha1.AddonGeneratedSecret{
+		{
+			Name: "addon-token",
+			Keys: []string{"token", "session-key"},
+		},
+	}
+	return addon
+}
@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+type ensureHPAResult int
+
+const (
+	ensureHPAResultNil   ensureHPAResult = iota
+	ensureHPAResultStop  ensureHPAResult = iota
+	ensureHPAResultRetry ensureHPAResult = iota
+)
+
+// Ensures the HorizontalPodAutoscaler specified in the given Addon resource
+// and the cleanup of HorizontalPodAutoscalers that are not needed anymore.
+// returns an ensureHPAResult that signals the caller if they have to
+// stop or retry reconciliation of the surrounding Addon resource
+func (r *AddonReconciler) ensureHPA(
+	ctx context.Context, addon *addonsv1alpha1.Addon,
+) (ensureHPAResult, error) {
+	targetNamespace := getCommonInstallOptions(addon).Namespace
+
+	var wantedHPAName string
+	if addon.Spec.HPA != nil {
+		if err := validateHPA(addon.Spec.HPA); err != nil {
+			// invalid configuration
+			return ensureHPAResultStop, r.reportConfigurationError(ctx, addon,
+				fmt.Sprintf(".spec.hpa is invalid: %s", err))
+		}
+
+		// the HPA would never scale anything, if its target doesn't exist
+		err := r.apiReader().Get(ctx, client.ObjectKey{
+			Name:      addon.Spec.HPA.DeploymentName,
+			Namespace: targetNamespace,
+		}, &appsv1.Deployment{})
+		if k8sApiErrors.IsNotFound(err) {
+			return ensureHPAResultRetry, r.reportInvalidHPATarget(ctx, addon)
+		}
+		if err != nil {
+			return ensureHPAResultNil, fmt.Errorf("getting HPA target Deployment: %w", err)
+		}
+
+		desiredHPA := newHPA(addon.Name, targetNamespace, addon.Spec.HPA)
+		addCommonLabels(desiredHPA.Labels, addon)
+		if err := controllerutil.SetControllerReference(addon, desiredHPA, r.Scheme); err != nil {
+			return ensureHPAResultNil, fmt.Errorf("setting controller reference: %w", err)
+		}
+
+		if err := r.reconcileHPA(ctx, desiredHPA); err != nil {
+			return ensureHPAResultNil, fmt.Errorf("reconciling HorizontalPodAutoscaler: %w", err)
+		}
+		wantedHPAName = desiredHPA.Name
+	}
+
+	// Ensure cleanup of HorizontalPodAutoscalers that were previously created for this Addon
+	hpas := &autoscalingv2beta2.HorizontalPodAutoscalerList{}
+	if err := r.List(ctx, hpas,
+		client.InNamespace(targetNamespace),
+		client.MatchingLabelsSelector{
+			Selector: commonLabelsAsLabelSelector(addon),
+		}); err != nil {
+		return ensureHPAResultNil, fmt.Errorf("could not list owned HorizontalPodAutoscalers: %w", err)
+	}
+	for i := range hpas.Items {
+		hpa := &hpas.Items[i]
+		if hpa.Name == wantedHPAName {
+			continue
+		}
+
+		err := r.Delete(ctx, hpa)
+		// don't propagate error if the HorizontalPodAutoscaler is already gone
+		if err != nil && !k8sApiErrors.IsNotFound(err) {
+			return ensureHPAResultNil, fmt.Errorf("could not delete HorizontalPodAutoscaler: %w", err)
+		}
+	}
+
+	return ensureHPAResultNil, nil
+}
+
+// Report Addon status to communicate that the HPA target Deployment does not exist
+func (r *AddonReconciler) reportInvalidHPATarget(
+	ctx context.Context, addon *addonsv1alpha1.Addon) error {
+	meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+		Type:   addonsv1alpha1.Available,
+		Status: metav1.ConditionFalse,
+		Reason: "InvalidHPATarget",
+		Message: fmt.Sprintf(
+			"HorizontalPodAutoscaler target Deployment %q does not exist",
+			addon.Spec.HPA.DeploymentName),
+		ObservedGeneration: addon.Generation,
+	})
+	addon.Status.ObservedGeneration = addon.Generation
+	addon.Status.Phase = addonsv1alpha1.PhasePending
+	return r.updateAddonStatus(ctx, addon)
+}
+
+// Builds a HorizontalPodAutoscaler scaling the Deployment referenced by the given AddonHPA.
+func newHPA(name, namespace string, hpa *addonsv1alpha1.AddonHPA) *autoscalingv2beta2.HorizontalPodAutoscaler {
+	// explicitly default, so the spec can be compared to the defaulted object
+	minReplicas := hpa.MinReplicas
+	if minReplicas == nil {
+		minReplicas = pointer.Int32Ptr(1)
+	}
+
+	return &autoscalingv2beta2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{},
+		},
+		Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2beta2.CrossVersionObjectReference{
+				APIVersion: appsv1.SchemeGroupVersion.String(),
+				Kind:       "Deployment",
+				Name:       hpa.DeploymentName,
+			},
+			MinReplicas: pointer.Int32Ptr(*minReplicas),
+			MaxReplicas: hpa.MaxReplicas,
+			Metrics: []autoscalingv2beta2.MetricSpec{
+				{
+					Type: autoscalingv2beta2.ResourceMetricSourceType,
+					Resource: &autoscalingv2beta2.ResourceMetricSource{
+						Name: corev1.ResourceCPU,
+						Target: autoscalingv2beta2.MetricTarget{
+							Type:               autoscalingv2beta2.UtilizationMetricType,
+							AverageUtilization: pointer.Int32Ptr(hpa.TargetCPUUtilizationPercentage),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Reconciles the target, replica limits and metrics of the given HorizontalPodAutoscaler
+// by creating or updating the HorizontalPodAutoscaler if needed.
+func (r *AddonReconciler) reconcileHPA(
+	ctx context.Context, hpa *autoscalingv2beta2.HorizontalPodAutoscaler) error {
+	currentHPA := &autoscalingv2beta2.HorizontalPodAutoscaler{}
+
+	err := r.Get(ctx, client.ObjectKeyFromObject(hpa), currentHPA)
+	if k8sApiErrors.IsNotFound(err) {
+		return r.Create(ctx, hpa)
+	}
+	if err != nil {
+		return fmt.Errorf("getting HorizontalPodAutoscaler: %w", err)
+	}
+
+	if !equality.Semantic.DeepEqual(currentHPA.Spec.ScaleTargetRef, hpa.Spec.ScaleTargetRef) ||
+		!equality.Semantic.DeepEqual(currentHPA.Spec.MinReplicas, hpa.Spec.MinReplicas) ||
+		currentHPA.Spec.MaxReplicas != hpa.Spec.MaxReplicas ||
+		!equality.Semantic.DeepEqual(currentHPA.Spec.Metrics, hpa.Spec.Metrics) {
+		currentHPA.Spec.ScaleTargetRef = hpa.Spec.ScaleTargetRef
+		currentHPA.Spec.MinReplicas = hpa.Spec.MinReplicas
+		currentHPA.Spec.MaxReplicas = hpa.Spec.MaxReplicas
+		currentHPA.Spec.Metrics = hpa.Spec.Metrics
+		return r.Update(ctx, currentHPA)
+	}
+	return nil
+}
+
+// Validates the target reference and replica limits of the given AddonHPA.
+func validateHPA(hpa *addonsv1alpha1.AddonHPA) error {
+	if errs := validation.IsDNS1123Subdomain(hpa.DeploymentName); len(errs) > 0 {
+		return fmt.Errorf("invalid deploymentName %q: %s",
+			hpa.DeploymentName, strings.Join(errs, ", "))
+	}
+	if hpa.MinReplicas != nil && *hpa.MinReplicas > hpa.MaxReplicas {
+		return fmt.Errorf("minReplicas %d is greater than maxReplicas %d",
+			*hpa.MinReplicas, hpa.MaxReplicas)
+	}
+	return nil
+}
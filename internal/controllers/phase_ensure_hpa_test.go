@@ -0,0 +1,227 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestEnsureHPA(t *testing.T) {
+	t.Run("creates HPA", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithHPA()
+
+		c.On("Get", mock.Anything, client.ObjectKey{
+			Name:      "manager",
+			Namespace: "addon-1",
+		}, mock.IsType(&appsv1.Deployment{})).
+			Return(nil)
+		c.On("Get", mock.Anything, client.ObjectKey{
+			Name:      addon.Name,
+			Namespace: "addon-1",
+		}, mock.IsType(&autoscalingv2beta2.HorizontalPodAutoscaler{})).
+			Return(newTestErrNotFound())
+		var createdHPA *autoscalingv2beta2.HorizontalPodAutoscaler
+		c.On("Create", mock.Anything,
+			mock.IsType(&autoscalingv2beta2.HorizontalPodAutoscaler{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				createdHPA = args.Get(1).(*autoscalingv2beta2.HorizontalPodAutoscaler)
+			}).
+			Return(nil)
+		c.On("List", mock.Anything,
+			mock.IsType(&autoscalingv2beta2.HorizontalPodAutoscalerList{}), mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		result, err := r.ensureHPA(ctx, addon)
+		require.NoError(t, err)
+		assert.Equal(t, ensureHPAResultNil, result)
+
+		c.AssertExpectations(t)
+		if assert.NotNil(t, createdHPA) {
+			assert.Equal(t, "manager", createdHPA.Spec.ScaleTargetRef.Name)
+			assert.Equal(t, "Deployment", createdHPA.Spec.ScaleTargetRef.Kind)
+			assert.Equal(t, pointer.Int32Ptr(1), createdHPA.Spec.MinReplicas)
+			assert.Equal(t, int32(5), createdHPA.Spec.MaxReplicas)
+			if assert.Len(t, createdHPA.Spec.Metrics, 1) {
+				assert.Equal(t, pointer.Int32Ptr(75),
+					createdHPA.Spec.Metrics[0].Resource.Target.AverageUtilization)
+			}
+			assert.Equal(t, addon.Name, createdHPA.Labels[commonInstanceLabel])
+		}
+	})
+
+	t.Run("deletes HPA removed from spec", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithHPA()
+		addon.Spec.HPA = nil
+
+		c.On("List", mock.Anything,
+			mock.IsType(&autoscalingv2beta2.HorizontalPodAutoscalerList{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				list := args.Get(1).(*autoscalingv2beta2.HorizontalPodAutoscalerList)
+				list.Items = []autoscalingv2beta2.HorizontalPodAutoscaler{
+					*newHPA(addon.Name, "addon-1", newTestAddonWithHPA().Spec.HPA),
+				}
+			}).
+			Return(nil)
+		c.On("Delete", mock.Anything,
+			mock.IsType(&autoscalingv2beta2.HorizontalPodAutoscaler{}), mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		result, err := r.ensureHPA(ctx, addon)
+		require.NoError(t, err)
+		assert.Equal(t, ensureHPAResultNil, result)
+
+		c.AssertExpectations(t)
+		c.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing target Deployment", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithHPA()
+
+		c.On("Get", mock.Anything, client.ObjectKey{
+			Name:      "manager",
+			Namespace: "addon-1",
+		}, mock.IsType(&appsv1.Deployment{})).
+			Return(newTestErrNotFound())
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		result, err := r.ensureHPA(ctx, addon)
+		require.NoError(t, err)
+		assert.Equal(t, ensureHPAResultRetry, result)
+
+		c.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+		availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+		if assert.NotNil(t, availableCond) {
+			assert.Equal(t, metav1.ConditionFalse, availableCond.Status)
+			assert.Equal(t, "InvalidHPATarget", availableCond.Reason)
+		}
+	})
+
+	t.Run("invalid replica limits", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithHPA()
+		addon.Spec.HPA.MinReplicas = pointer.Int32Ptr(10)
+
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		result, err := r.ensureHPA(ctx, addon)
+		require.NoError(t, err)
+		assert.Equal(t, ensureHPAResultStop, result)
+
+		availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+		if assert.NotNil(t, availableCond) {
+			assert.Equal(t, "ConfigurationError", availableCond.Reason)
+		}
+	})
+}
+
+func TestReconcileHPA(t *testing.T) {
+	hpa := newHPA("addon-1", "addon-1", newTestAddonWithHPA().Spec.HPA)
+
+	t.Run("no-op when spec matches", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		c.On("Get", mock.Anything, client.ObjectKeyFromObject(hpa),
+			mock.IsType(&autoscalingv2beta2.HorizontalPodAutoscaler{})).
+			Run(func(args mock.Arguments) {
+				hpa.DeepCopyInto(args.Get(2).(*autoscalingv2beta2.HorizontalPodAutoscaler))
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.reconcileHPA(ctx, hpa.DeepCopy())
+		require.NoError(t, err)
+		c.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("corrects replica drift", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		c.On("Get", mock.Anything, client.ObjectKeyFromObject(hpa),
+			mock.IsType(&autoscalingv2beta2.HorizontalPodAutoscaler{})).
+			Run(func(args mock.Arguments) {
+				currentHPA := args.Get(2).(*autoscalingv2beta2.HorizontalPodAutoscaler)
+				hpa.DeepCopyInto(currentHPA)
+				currentHPA.ResourceVersion = "123"
+				currentHPA.Spec.MaxReplicas = 20
+			}).
+			Return(nil)
+		var updatedHPA *autoscalingv2beta2.HorizontalPodAutoscaler
+		c.On("Update", mock.Anything,
+			mock.IsType(&autoscalingv2beta2.HorizontalPodAutoscaler{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				updatedHPA = args.Get(1).(*autoscalingv2beta2.HorizontalPodAutoscaler)
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.reconcileHPA(ctx, hpa.DeepCopy())
+		require.NoError(t, err)
+
+		if assert.NotNil(t, updatedHPA) {
+			assert.Equal(t, int32(5), updatedHPA.Spec.MaxReplicas)
+			// update has to be done on the existing object
+			assert.Equal(t, "123", updatedHPA.ResourceVersion)
+		}
+	})
+}
+
+func newTestAddonWithHPA() *addonsv1alpha1.Addon {
+	addon := newTestAddonWithCatalogSourceImage()
+	addon.Spec.HPA = &addonsv1alpha1.AddonHPA{
+		DeploymentName:                 "manager",
+		MaxReplicas:                    5,
+		TargetCPUUtilizationPercentage: 75,
+	}
+	return addon
+}
@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+// Ensures the LimitRange specified in the given Addon resource
+// and the cleanup of LimitRanges that are not needed anymore.
+// returns a bool that signals the caller to stop reconciliation
+func (r *AddonReconciler) ensureLimitRange(
+	ctx context.Context, addon *addonsv1alpha1.Addon) (stop bool, err error) {
+	targetNamespace := getCommonInstallOptions(addon).Namespace
+
+	var wantedLimitRangeName string
+	if addon.Spec.LimitRange != nil {
+		if err := validateLimitRangeItems(addon.Spec.LimitRange.Limits); err != nil {
+			// invalid configuration
+			// TODO: Move error reporting into webhook and reduce this code to a sanity check.
+			return true, r.reportConfigurationError(ctx, addon,
+				fmt.Sprintf(".spec.limitRange is invalid: %s", err))
+		}
+
+		desiredLimitRange := &corev1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      addon.Name,
+				Namespace: targetNamespace,
+				Labels:    map[string]string{},
+			},
+			Spec: corev1.LimitRangeSpec{
+				Limits: addon.Spec.LimitRange.Limits,
+			},
+		}
+
+		addCommonLabels(desiredLimitRange.Labels, addon)
+		if err := controllerutil.SetControllerReference(addon, desiredLimitRange, r.Scheme); err != nil {
+			return false, fmt.Errorf("setting controller reference: %w", err)
+		}
+
+		if err := r.reconcileLimitRange(ctx, desiredLimitRange); err != nil {
+			return false, fmt.Errorf("reconciling LimitRange: %w", err)
+		}
+		wantedLimitRangeName = desiredLimitRange.Name
+	}
+
+	// Ensure cleanup of LimitRanges that were previously created for this Addon
+	limitRanges := &corev1.LimitRangeList{}
+	if err := r.List(ctx, limitRanges,
+		client.InNamespace(targetNamespace),
+		client.MatchingLabelsSelector{
+			Selector: commonLabelsAsLabelSelector(addon),
+		}); err != nil {
+		return false, fmt.Errorf("could not list owned LimitRanges: %w", err)
+	}
+	for i := range limitRanges.Items {
+		limitRange := &limitRanges.Items[i]
+		if limitRange.Name == wantedLimitRangeName {
+			continue
+		}
+
+		err := r.Delete(ctx, limitRange)
+		// don't propagate error if the LimitRange is already gone
+		if err != nil && !k8sApiErrors.IsNotFound(err) {
+			return false, fmt.Errorf("could not delete LimitRange: %w", err)
+		}
+	}
+
+	return false, nil
+}
+
+// Reconciles the Spec of the given LimitRange if needed by updating or creating the LimitRange.
+func (r *AddonReconciler) reconcileLimitRange(
+	ctx context.Context, limitRange *corev1.LimitRange) error {
+	currentLimitRange := &corev1.LimitRange{}
+
+	err := r.Get(ctx, client.ObjectKeyFromObject(limitRange), currentLimitRange)
+	if k8sApiErrors.IsNotFound(err) {
+		return r.Create(ctx, limitRange)
+	}
+	if err != nil {
+		return fmt.Errorf("getting LimitRange: %w", err)
+	}
+
+	// only update when spec has changed
+	if !equality.Semantic.DeepEqual(currentLimitRange.Spec, limitRange.Spec) {
+		currentLimitRange.Spec = limitRange.Spec
+		return r.Update(ctx, currentLimitRange)
+	}
+	return nil
+}
+
+// Checks that no minimum of a resource exceeds its maximum.
+// Resources are checked in order, so the same error is reported on every reconcile.
+func validateLimitRangeItems(items []corev1.LimitRangeItem) error {
+	for _, item := range items {
+		resourceNames := make([]string, 0, len(item.Max))
+		for resourceName := range item.Max {
+			resourceNames = append(resourceNames, string(resourceName))
+		}
+		sort.Strings(resourceNames)
+
+		for _, name := range resourceNames {
+			resourceName := corev1.ResourceName(name)
+			max := item.Max[resourceName]
+			min, ok := item.Min[resourceName]
+			if !ok {
+				continue
+			}
+
+			if min.Cmp(max) > 0 {
+				return fmt.Errorf(
+					"min %s of %s resource %s is greater than max %s",
+					min.String(), item.Type, resourceName, max.String())
+			}
+		}
+	}
+	return nil
+}
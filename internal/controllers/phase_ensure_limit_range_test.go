@@ -0,0 +1,254 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestEnsureLimitRange(t *testing.T) {
+	t.Run("creates LimitRange", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithLimitRange()
+
+		c.On("Get", mock.Anything, client.ObjectKey{
+			Name:      addon.Name,
+			Namespace: "addon-1",
+		}, mock.IsType(&corev1.LimitRange{})).
+			Return(newTestErrNotFound())
+		var createdLimitRange *corev1.LimitRange
+		c.On("Create", mock.Anything, mock.IsType(&corev1.LimitRange{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				createdLimitRange = args.Get(1).(*corev1.LimitRange)
+			}).
+			Return(nil)
+		c.On("List", mock.Anything, mock.IsType(&corev1.LimitRangeList{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				list := args.Get(1).(*corev1.LimitRangeList)
+				list.Items = []corev1.LimitRange{*createdLimitRange}
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		stop, err := r.ensureLimitRange(ctx, addon)
+		require.NoError(t, err)
+		assert.False(t, stop)
+
+		c.AssertExpectations(t)
+		c.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+		if assert.NotNil(t, createdLimitRange) {
+			assert.Equal(t, addon.Spec.LimitRange.Limits, createdLimitRange.Spec.Limits)
+			assert.Equal(t, addon.Name, createdLimitRange.Labels[commonInstanceLabel])
+		}
+	})
+
+	t.Run("deletes LimitRange removed from spec", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithLimitRange()
+		addon.Spec.LimitRange = nil
+
+		c.On("List", mock.Anything, mock.IsType(&corev1.LimitRangeList{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				list := args.Get(1).(*corev1.LimitRangeList)
+				list.Items = []corev1.LimitRange{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      addon.Name,
+							Namespace: "addon-1",
+						},
+					},
+				}
+			}).
+			Return(nil)
+		c.On("Delete", mock.Anything, mock.IsType(&corev1.LimitRange{}), mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		stop, err := r.ensureLimitRange(ctx, addon)
+		require.NoError(t, err)
+		assert.False(t, stop)
+
+		c.AssertExpectations(t)
+		c.AssertCalled(t, "Delete", mock.Anything,
+			mock.MatchedBy(func(lr *corev1.LimitRange) bool {
+				return lr.Name == addon.Name && lr.Namespace == "addon-1"
+			}), mock.Anything)
+	})
+
+	t.Run("rejects min greater than max", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithLimitRange()
+		addon.Spec.LimitRange.Limits[0].Min = corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("2Gi"),
+		}
+
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		stop, err := r.ensureLimitRange(ctx, addon)
+		require.NoError(t, err)
+		assert.True(t, stop)
+
+		c.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+		c.StatusMock.AssertCalled(t, "Update", mock.Anything, addon, mock.Anything)
+		availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+		if assert.NotNil(t, availableCond) {
+			assert.Equal(t, metav1.ConditionFalse, availableCond.Status)
+			assert.Equal(t, "ConfigurationError", availableCond.Reason)
+		}
+	})
+}
+
+func TestReconcileLimitRange(t *testing.T) {
+	limitRange := &corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "addon-1",
+			Namespace: "addon-1",
+		},
+		Spec: corev1.LimitRangeSpec{
+			Limits: newTestAddonWithLimitRange().Spec.LimitRange.Limits,
+		},
+	}
+
+	t.Run("no-op", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		c.On("Get", mock.Anything, client.ObjectKeyFromObject(limitRange),
+			mock.IsType(&corev1.LimitRange{})).
+			Run(func(args mock.Arguments) {
+				limitRange.DeepCopyInto(args.Get(2).(*corev1.LimitRange))
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.reconcileLimitRange(ctx, limitRange.DeepCopy())
+		require.NoError(t, err)
+		c.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		c.On("Get", mock.Anything, client.ObjectKeyFromObject(limitRange),
+			mock.IsType(&corev1.LimitRange{})).
+			Run(func(args mock.Arguments) {
+				lr := args.Get(2).(*corev1.LimitRange)
+				limitRange.DeepCopyInto(lr)
+				lr.Spec.Limits[0].Max = corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("4Gi"),
+				}
+			}).
+			Return(nil)
+		var updatedLimitRange *corev1.LimitRange
+		c.On("Update", mock.Anything, mock.IsType(&corev1.LimitRange{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				updatedLimitRange = args.Get(1).(*corev1.LimitRange)
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.reconcileLimitRange(ctx, limitRange.DeepCopy())
+		require.NoError(t, err)
+		if assert.NotNil(t, updatedLimitRange) {
+			assert.Equal(t, limitRange.Spec, updatedLimitRange.Spec)
+		}
+	})
+}
+
+func TestValidateLimitRangeItems(t *testing.T) {
+	assert.NoError(t, validateLimitRangeItems(
+		newTestAddonWithLimitRange().Spec.LimitRange.Limits))
+
+	assert.Error(t, validateLimitRangeItems([]corev1.LimitRangeItem{
+		{
+			Type: corev1.LimitTypeContainer,
+			Min: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("2"),
+			},
+			Max: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("500m"),
+			},
+		},
+	}))
+
+	// multiple violations are reported in a stable order
+	invalidItems := []corev1.LimitRangeItem{
+		{
+			Type: corev1.LimitTypeContainer,
+			Min: corev1.ResourceList{
+				corev1.ResourceCPU:              resource.MustParse("2"),
+				corev1.ResourceMemory:           resource.MustParse("2Gi"),
+				corev1.ResourceEphemeralStorage: resource.MustParse("2Gi"),
+			},
+			Max: corev1.ResourceList{
+				corev1.ResourceCPU:              resource.MustParse("500m"),
+				corev1.ResourceMemory:           resource.MustParse("1Gi"),
+				corev1.ResourceEphemeralStorage: resource.MustParse("1Gi"),
+			},
+		},
+	}
+	for i := 0; i < 10; i++ {
+		assert.EqualError(t, validateLimitRangeItems(invalidItems),
+			"min 2 of Container resource cpu is greater than max 500m")
+	}
+}
+
+func newTestAddonWithLimitRange() *addonsv1alpha1.Addon {
+	addon := newTestAddonWithCatalogSourceImage()
+	addon.Spec.LimitRange = &addonsv1alpha1.AddonLimitRange{
+		Limits: []corev1.LimitRangeItem{
+			{
+				Type: corev1.LimitTypeContainer,
+				Default: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("512Mi"),
+				},
+				DefaultRequest: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("256Mi"),
+				},
+				Min: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("128Mi"),
+				},
+				Max: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				},
+			},
+		},
+	}
+	return addon
+}
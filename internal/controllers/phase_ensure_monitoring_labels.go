@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+// Labels platform monitoring requires on a Namespace to scrape metrics from it.
+var monitoringLabels = map[string]string{
+	"openshift.io/cluster-monitoring": "true",
+}
+
+// Adds the labels required by platform monitoring, if enabled for the given Addon.
+func addMonitoringLabels(labels map[string]string, addon *addonsv1alpha1.Addon) {
+	if labels == nil || !addon.Spec.ClusterMonitoring {
+		return
+	}
+
+	for label, value := range monitoringLabels {
+		labels[label] = value
+	}
+}
+
+// Ensures the labels required by platform monitoring are present
+// on all Namespaces of the given Addon and re-applies them when removed.
+// Drift is recorded in the MonitoringLabelsDrift status field,
+// the labels are removed again when monitoring is disabled.
+func (r *AddonReconciler) ensureMonitoringLabels(
+	ctx context.Context, addon *addonsv1alpha1.Addon) error {
+	if !addon.Spec.ClusterMonitoring {
+		return r.removeMonitoringLabels(ctx, addon)
+	}
+
+	var driftedNamespaces []string
+	for _, addonNamespace := range addon.Spec.Namespaces {
+		namespace := &corev1.Namespace{}
+		err := r.Get(ctx, client.ObjectKey{Name: addonNamespace.Name}, namespace)
+		if k8sApiErrors.IsNotFound(err) {
+			// the Namespace phase takes care of missing Namespaces
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("getting Namespace: %w", err)
+		}
+
+		if !hasMonitoringLabels(namespace) {
+			if namespace.Labels == nil {
+				namespace.Labels = map[string]string{}
+			}
+			addMonitoringLabels(namespace.Labels, addon)
+			if err := r.Update(ctx, namespace); err != nil {
+				return fmt.Errorf("re-applying monitoring labels: %w", err)
+			}
+			driftedNamespaces = append(driftedNamespaces, namespace.Name)
+		}
+	}
+
+	if len(driftedNamespaces) == 0 {
+		return nil
+	}
+	addon.Status.MonitoringLabelsDrift = &addonsv1alpha1.MonitoringLabelsDrift{
+		ObservedTime: metav1.Now(),
+		Namespaces:   driftedNamespaces,
+	}
+	return r.updateAddonStatus(ctx, addon)
+}
+
+// Removes the labels required by platform monitoring
+// from all Namespaces controlled by the given Addon.
+func (r *AddonReconciler) removeMonitoringLabels(
+	ctx context.Context, addon *addonsv1alpha1.Addon) error {
+	for _, addonNamespace := range addon.Spec.Namespaces {
+		namespace := &corev1.Namespace{}
+		err := r.Get(ctx, client.ObjectKey{Name: addonNamespace.Name}, namespace)
+		if k8sApiErrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("getting Namespace: %w", err)
+		}
+
+		// labels on Namespaces we don't own are not ours to remove
+		if !metav1.IsControlledBy(namespace, addon) ||
+			!removeMonitoringLabelsFromNamespace(namespace) {
+			continue
+		}
+		if err := r.Update(ctx, namespace); err != nil {
+			return fmt.Errorf("removing monitoring labels: %w", err)
+		}
+	}
+
+	if addon.Status.MonitoringLabelsDrift == nil {
+		return nil
+	}
+	addon.Status.MonitoringLabelsDrift = nil
+	return r.updateAddonStatus(ctx, addon)
+}
+
+func hasMonitoringLabels(namespace *corev1.Namespace) bool {
+	for label, value := range monitoringLabels {
+		if namespace.Labels[label] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func removeMonitoringLabelsFromNamespace(namespace *corev1.Namespace) (changed bool) {
+	for label := range monitoringLabels {
+		if _, ok := namespace.Labels[label]; ok {
+			delete(namespace.Labels, label)
+			changed = true
+		}
+	}
+	return changed
+}
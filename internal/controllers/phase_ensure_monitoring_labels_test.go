@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestEnsureMonitoringLabels(t *testing.T) {
+	t.Run("re-applies stripped label and keeps the observed drift", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithSingleNamespace()
+		addon.Spec.ClusterMonitoring = true
+
+		// label was stripped by someone
+		c.On("Get", mock.Anything, client.ObjectKey{Name: "namespace-1"},
+			mock.IsType(&corev1.Namespace{})).
+			Run(func(args mock.Arguments) {
+				ns := args.Get(2).(*corev1.Namespace)
+				ns.Name = "namespace-1"
+				ns.Labels = map[string]string{commonInstanceLabel: addon.Name}
+			}).
+			Return(nil).Once()
+		var updatedNamespace *corev1.Namespace
+		c.On("Update", mock.Anything, mock.IsType(&corev1.Namespace{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				updatedNamespace = args.Get(1).(*corev1.Namespace)
+			}).
+			Return(nil)
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		require.NoError(t, r.ensureMonitoringLabels(ctx, addon))
+
+		if assert.NotNil(t, updatedNamespace) {
+			assert.Equal(t, "true", updatedNamespace.Labels["openshift.io/cluster-monitoring"])
+			assert.Equal(t, addon.Name, updatedNamespace.Labels[commonInstanceLabel])
+		}
+		drift := addon.Status.MonitoringLabelsDrift
+		if assert.NotNil(t, drift) {
+			assert.Equal(t, []string{"namespace-1"}, drift.Namespaces)
+			assert.False(t, drift.ObservedTime.IsZero())
+		}
+
+		// label is back in place
+		c.On("Get", mock.Anything, client.ObjectKey{Name: "namespace-1"},
+			mock.IsType(&corev1.Namespace{})).
+			Run(func(args mock.Arguments) {
+				updatedNamespace.DeepCopyInto(args.Get(2).(*corev1.Namespace))
+			}).
+			Return(nil)
+
+		require.NoError(t, r.ensureMonitoringLabels(ctx, addon))
+
+		c.AssertNumberOfCalls(t, "Update", 1)
+		c.StatusMock.AssertNumberOfCalls(t, "Update", 1)
+		assert.Equal(t, drift, addon.Status.MonitoringLabelsDrift)
+	})
+
+	t.Run("removes labels and drift when cluster monitoring is disabled", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithSingleNamespace()
+		addon.UID = "addon-uid"
+		addon.Status.MonitoringLabelsDrift = &addonsv1alpha1.MonitoringLabelsDrift{
+			ObservedTime: metav1.Now(),
+			Namespaces:   []string{"namespace-1"},
+		}
+
+		c.On("Get", mock.Anything, client.ObjectKey{Name: "namespace-1"},
+			mock.IsType(&corev1.Namespace{})).
+			Run(func(args mock.Arguments) {
+				ns := args.Get(2).(*corev1.Namespace)
+				ns.Name = "namespace-1"
+				ns.Labels = map[string]string{
+					commonInstanceLabel:               addon.Name,
+					"openshift.io/cluster-monitoring": "true",
+				}
+				require.NoError(t,
+					controllerutil.SetControllerReference(addon, ns, r.Scheme))
+			}).
+			Return(nil)
+		var updatedNamespace *corev1.Namespace
+		c.On("Update", mock.Anything, mock.IsType(&corev1.Namespace{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				updatedNamespace = args.Get(1).(*corev1.Namespace)
+			}).
+			Return(nil)
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		require.NoError(t, r.ensureMonitoringLabels(ctx, addon))
+
+		if assert.NotNil(t, updatedNamespace) {
+			assert.NotContains(t, updatedNamespace.Labels, "openshift.io/cluster-monitoring")
+			assert.Equal(t, addon.Name, updatedNamespace.Labels[commonInstanceLabel])
+		}
+		assert.Nil(t, addon.Status.MonitoringLabelsDrift)
+		c.StatusMock.AssertCalled(t, "Update", mock.Anything, addon, mock.Anything)
+	})
+
+	t.Run("keeps labels on foreign Namespaces when cluster monitoring is disabled", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithSingleNamespace()
+		addon.UID = "addon-uid"
+
+		c.On("Get", mock.Anything, client.ObjectKey{Name: "namespace-1"},
+			mock.IsType(&corev1.Namespace{})).
+			Run(func(args mock.Arguments) {
+				ns := args.Get(2).(*corev1.Namespace)
+				ns.Name = "namespace-1"
+				ns.Labels = map[string]string{
+					"openshift.io/cluster-monitoring": "true",
+				}
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		require.NoError(t, r.ensureMonitoringLabels(ctx, addon))
+		c.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+		c.StatusMock.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestAddMonitoringLabels(t *testing.T) {
+	addon := newTestAddonWithSingleNamespace()
+
+	labels := map[string]string{}
+	addMonitoringLabels(labels, addon)
+	assert.Empty(t, labels)
+
+	addon.Spec.ClusterMonitoring = true
+	addMonitoringLabels(labels, addon)
+	assert.Equal(t, map[string]string{
+		"openshift.io/cluster-monitoring": "true",
+	}, labels)
+}
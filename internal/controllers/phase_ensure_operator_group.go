@@ -59,8 +59,15 @@ func (r *AddonReconciler) reconcileOperatorGroup(
 		return fmt.Errorf("getting OperatorGroup: %w", err)
 	}
 
-	if !equality.Semantic.DeepEqual(currentOperatorGroup.Spec, operatorGroup.Spec) {
-		return r.Update(ctx, operatorGroup)
+	labelsChanged := reconcileCommonLabels(currentOperatorGroup, operatorGroup)
+	// only update when spec or labels have changed, e.g. targetNamespaces were edited out-of-band
+	if labelsChanged || !equality.Semantic.DeepEqual(currentOperatorGroup.Spec, operatorGroup.Spec) {
+		// copy new spec into existing object and update in the k8s api
+		currentOperatorGroup.Spec = operatorGroup.Spec
+		if err := r.Update(ctx, currentOperatorGroup); err != nil {
+			return fmt.Errorf("updating OperatorGroup: %w", err)
+		}
 	}
+	currentOperatorGroup.DeepCopyInto(operatorGroup)
 	return nil
 }
@@ -129,6 +129,88 @@ func TestEnsureOperatorGroup(t *testing.T) {
 		}
 	})
 
+	t.Run("corrects drift of targetNamespaces", func(t *testing.T) {
+		tests := []struct {
+			name                     string
+			addon                    *addonsv1alpha1.Addon
+			currentTargetNamespaces  []string
+			expectedTargetNamespaces []string
+		}{
+			{
+				name:                     "OwnNamespace",
+				addon:                    newTestAddonWithCatalogSourceImage(),
+				currentTargetNamespaces:  []string{"addon-1", "other-namespace"},
+				expectedTargetNamespaces: []string{"addon-1"},
+			},
+			{
+				name: "AllNamespaces",
+				addon: func() *addonsv1alpha1.Addon {
+					addon := newTestAddonWithCatalogSourceImage()
+					addon.Spec.Install = addonsv1alpha1.AddonInstallSpec{
+						Type: addonsv1alpha1.OLMAllNamespaces,
+						OLMAllNamespaces: &addonsv1alpha1.AddonInstallOLMAllNamespaces{
+							AddonInstallOLMCommon: addon.Spec.Install.OLMOwnNamespace.AddonInstallOLMCommon,
+						},
+					}
+					return addon
+				}(),
+				currentTargetNamespaces: []string{"addon-1"},
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				log := testutil.NewLogger(t)
+				c := testutil.NewClient()
+				r := AddonReconciler{
+					Client: c,
+					Scheme: newTestSchemeWithAddonsv1alpha1(),
+				}
+				addon := test.addon
+
+				c.
+					On(
+						"Get",
+						mock.Anything,
+						client.ObjectKey{
+							Name:      addon.Name,
+							Namespace: "addon-1",
+						},
+						mock.IsType(&operatorsv1.OperatorGroup{}),
+					).
+					Run(func(args mock.Arguments) {
+						og := args.Get(2).(*operatorsv1.OperatorGroup)
+						og.Name = addon.Name
+						og.Namespace = "addon-1"
+						og.Spec.TargetNamespaces = test.currentTargetNamespaces
+					}).
+					Return(nil)
+				var updatedOperatorGroup *operatorsv1.OperatorGroup
+				c.
+					On(
+						"Update",
+						mock.Anything,
+						mock.IsType(&operatorsv1.OperatorGroup{}),
+						mock.Anything,
+					).
+					Run(func(args mock.Arguments) {
+						updatedOperatorGroup = args.Get(1).(*operatorsv1.OperatorGroup)
+					}).
+					Return(nil)
+
+				ctx := context.Background()
+				stop, err := r.ensureOperatorGroup(ctx, log, addon)
+				require.NoError(t, err)
+				assert.False(t, stop)
+
+				if assert.NotNil(t, updatedOperatorGroup) {
+					assert.Equal(t, test.expectedTargetNamespaces,
+						updatedOperatorGroup.Spec.TargetNamespaces)
+				}
+			})
+		}
+	})
+
 	t.Run("guards against invalid configuration", func(t *testing.T) {
 		tests := []struct {
 			name  string
@@ -307,8 +389,15 @@ func TestReconcileOperatorGroup(t *testing.T) {
 				client.ObjectKeyFromObject(operatorGroup),
 				mock.IsType(&operatorsv1.OperatorGroup{}),
 			).
+			Run(func(args mock.Arguments) {
+				og := args.Get(2).(*operatorsv1.OperatorGroup)
+				operatorGroup.DeepCopyInto(og)
+				og.ResourceVersion = "123"
+				og.Spec.TargetNamespaces = []string{"something-else"}
+			}).
 			Return(nil)
 
+		var updatedOperatorGroup *operatorsv1.OperatorGroup
 		c.
 			On(
 				"Update",
@@ -316,6 +405,9 @@ func TestReconcileOperatorGroup(t *testing.T) {
 				mock.IsType(&operatorsv1.OperatorGroup{}),
 				mock.Anything,
 			).
+			Run(func(args mock.Arguments) {
+				updatedOperatorGroup = args.Get(1).(*operatorsv1.OperatorGroup)
+			}).
 			Return(nil)
 
 		ctx := context.Background()
@@ -328,5 +420,10 @@ func TestReconcileOperatorGroup(t *testing.T) {
 			mock.IsType(&operatorsv1.OperatorGroup{}),
 			mock.Anything,
 		)
+		if assert.NotNil(t, updatedOperatorGroup) {
+			assert.Equal(t, operatorGroup.Spec, updatedOperatorGroup.Spec)
+			// update has to be done on the existing object
+			assert.Equal(t, "123", updatedOperatorGroup.ResourceVersion)
+		}
 	})
 }
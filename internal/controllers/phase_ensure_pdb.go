@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+// Ensures the PodDisruptionBudget specified in the given Addon resource
+// and the cleanup of PodDisruptionBudgets that are not needed anymore.
+// returns a bool that signals the caller to stop reconciliation
+func (r *AddonReconciler) ensurePDB(
+	ctx context.Context, addon *addonsv1alpha1.Addon) (stop bool, err error) {
+	targetNamespace := getCommonInstallOptions(addon).Namespace
+
+	var wantedPDBName string
+	if addon.Spec.PDB != nil {
+		if err := validatePDB(addon.Spec.PDB); err != nil {
+			// invalid configuration
+			return true, r.reportConfigurationError(ctx, addon,
+				fmt.Sprintf(".spec.pdb is invalid: %s", err))
+		}
+
+		desiredPDB := newPDB(addon.Name, targetNamespace, addon.Spec.PDB)
+		addCommonLabels(desiredPDB.Labels, addon)
+		if err := controllerutil.SetControllerReference(addon, desiredPDB, r.Scheme); err != nil {
+			return false, fmt.Errorf("setting controller reference: %w", err)
+		}
+
+		if err := r.reconcilePDB(ctx, desiredPDB); err != nil {
+			return false, fmt.Errorf("reconciling PodDisruptionBudget: %w", err)
+		}
+		wantedPDBName = desiredPDB.Name
+	}
+
+	// Ensure cleanup of PodDisruptionBudgets that were previously created for this Addon
+	pdbs := &policyv1beta1.PodDisruptionBudgetList{}
+	if err := r.List(ctx, pdbs,
+		client.InNamespace(targetNamespace),
+		client.MatchingLabelsSelector{
+			Selector: commonLabelsAsLabelSelector(addon),
+		}); err != nil {
+		return false, fmt.Errorf("could not list owned PodDisruptionBudgets: %w", err)
+	}
+	for i := range pdbs.Items {
+		pdb := &pdbs.Items[i]
+		if pdb.Name == wantedPDBName {
+			continue
+		}
+
+		err := r.Delete(ctx, pdb)
+		// don't propagate error if the PodDisruptionBudget is already gone
+		if err != nil && !k8sApiErrors.IsNotFound(err) {
+			return false, fmt.Errorf("could not delete PodDisruptionBudget: %w", err)
+		}
+	}
+
+	return false, nil
+}
+
+// Builds a PodDisruptionBudget protecting the Pods selected by the given AddonPDB.
+func newPDB(name, namespace string, pdb *addonsv1alpha1.AddonPDB) *policyv1beta1.PodDisruptionBudget {
+	return &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{},
+		},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			Selector:       pdb.Selector.DeepCopy(),
+			MinAvailable:   pdb.MinAvailable,
+			MaxUnavailable: pdb.MaxUnavailable,
+		},
+	}
+}
+
+// Reconciles the selector and availability limits of the given PodDisruptionBudget
+// by creating or updating the PodDisruptionBudget if needed.
+func (r *AddonReconciler) reconcilePDB(
+	ctx context.Context, pdb *policyv1beta1.PodDisruptionBudget) error {
+	currentPDB := &policyv1beta1.PodDisruptionBudget{}
+
+	err := r.Get(ctx, client.ObjectKeyFromObject(pdb), currentPDB)
+	if k8sApiErrors.IsNotFound(err) {
+		return r.Create(ctx, pdb)
+	}
+	if err != nil {
+		return fmt.Errorf("getting PodDisruptionBudget: %w", err)
+	}
+
+	if !equality.Semantic.DeepEqual(currentPDB.Spec.Selector, pdb.Spec.Selector) ||
+		!equality.Semantic.DeepEqual(currentPDB.Spec.MinAvailable, pdb.Spec.MinAvailable) ||
+		!equality.Semantic.DeepEqual(currentPDB.Spec.MaxUnavailable, pdb.Spec.MaxUnavailable) {
+		currentPDB.Spec.Selector = pdb.Spec.Selector
+		currentPDB.Spec.MinAvailable = pdb.Spec.MinAvailable
+		currentPDB.Spec.MaxUnavailable = pdb.Spec.MaxUnavailable
+		return r.Update(ctx, currentPDB)
+	}
+	return nil
+}
+
+// Validates the selector and availability limits of the given AddonPDB.
+func validatePDB(pdb *addonsv1alpha1.AddonPDB) error {
+	if (pdb.MinAvailable == nil) == (pdb.MaxUnavailable == nil) {
+		return fmt.Errorf("exactly one of minAvailable and maxUnavailable has to be set")
+	}
+	if _, err := metav1.LabelSelectorAsSelector(&pdb.Selector); err != nil {
+		return fmt.Errorf("invalid selector: %w", err)
+	}
+
+	limit := pdb.MinAvailable
+	if limit == nil {
+		limit = pdb.MaxUnavailable
+	}
+	if _, err := intstr.GetScaledValueFromIntOrPercent(limit, 100, true); err != nil {
+		return fmt.Errorf("invalid limit: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestEnsurePDB(t *testing.T) {
+	t.Run("creates PDB", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithPDB()
+
+		c.ExpectNotFound(client.ObjectKey{
+			Name:      addon.Name,
+			Namespace: "addon-1",
+		}, &policyv1beta1.PodDisruptionBudget{})
+		createdPDB := &policyv1beta1.PodDisruptionBudget{}
+		c.ExpectCreate(createdPDB)
+		c.ExpectList(&policyv1beta1.PodDisruptionBudgetList{})
+
+		ctx := context.Background()
+		stop, err := r.ensurePDB(ctx, addon)
+		require.NoError(t, err)
+		assert.False(t, stop)
+
+		c.AssertExpectations(t)
+		minAvailable := intstr.FromInt(1)
+		assert.Equal(t, &minAvailable, createdPDB.Spec.MinAvailable)
+		assert.Nil(t, createdPDB.Spec.MaxUnavailable)
+		assert.Equal(t, map[string]string{"app": "manager"}, createdPDB.Spec.Selector.MatchLabels)
+		assert.Equal(t, addon.Name, createdPDB.Labels[commonInstanceLabel])
+	})
+
+	t.Run("deletes PDB removed from spec", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithPDB()
+		addon.Spec.PDB = nil
+
+		c.ExpectList(&policyv1beta1.PodDisruptionBudgetList{},
+			newPDB(addon.Name, "addon-1", newTestAddonWithPDB().Spec.PDB))
+		c.ExpectDelete(&policyv1beta1.PodDisruptionBudget{})
+
+		ctx := context.Background()
+		stop, err := r.ensurePDB(ctx, addon)
+		require.NoError(t, err)
+		assert.False(t, stop)
+
+		c.AssertExpectations(t)
+		c.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects conflicting limits", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithPDB()
+		maxUnavailable := intstr.FromString("50%")
+		addon.Spec.PDB.MaxUnavailable = &maxUnavailable
+
+		c.ExpectStatusUpdate()
+
+		ctx := context.Background()
+		stop, err := r.ensurePDB(ctx, addon)
+		require.NoError(t, err)
+		assert.True(t, stop)
+
+		c.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+		availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+		if assert.NotNil(t, availableCond) {
+			assert.Equal(t, metav1.ConditionFalse, availableCond.Status)
+			assert.Equal(t, "ConfigurationError", availableCond.Reason)
+		}
+	})
+}
+
+func TestReconcilePDB(t *testing.T) {
+	pdb := newPDB("addon-1", "addon-1", newTestAddonWithPDB().Spec.PDB)
+
+	t.Run("corrects limit drift", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		currentPDB := pdb.DeepCopy()
+		currentPDB.ResourceVersion = "123"
+		currentPDB.Spec.MinAvailable = nil
+		maxUnavailable := intstr.FromInt(2)
+		currentPDB.Spec.MaxUnavailable = &maxUnavailable
+		c.ExpectGet(currentPDB)
+		updatedPDB := &policyv1beta1.PodDisruptionBudget{}
+		c.ExpectUpdate(updatedPDB)
+
+		ctx := context.Background()
+		err := r.reconcilePDB(ctx, pdb.DeepCopy())
+		require.NoError(t, err)
+
+		minAvailable := intstr.FromInt(1)
+		assert.Equal(t, &minAvailable, updatedPDB.Spec.MinAvailable)
+		assert.Nil(t, updatedPDB.Spec.MaxUnavailable)
+		// update has to be done on the existing object
+		assert.Equal(t, "123", updatedPDB.ResourceVersion)
+	})
+
+	t.Run("no-op when spec matches", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		c.ExpectGet(pdb)
+
+		ctx := context.Background()
+		err := r.reconcilePDB(ctx, pdb.DeepCopy())
+		require.NoError(t, err)
+		c.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestValidatePDB(t *testing.T) {
+	one := intstr.FromInt(1)
+	half := intstr.FromString("50%")
+	invalid := intstr.FromString("half")
+
+	tests := []struct {
+		name  string
+		pdb   *addonsv1alpha1.AddonPDB
+		valid bool
+	}{
+		{name: "minAvailable", pdb: &addonsv1alpha1.AddonPDB{MinAvailable: &one}, valid: true},
+		{name: "maxUnavailable percentage", pdb: &addonsv1alpha1.AddonPDB{MaxUnavailable: &half}, valid: true},
+		{name: "both limits", pdb: &addonsv1alpha1.AddonPDB{MinAvailable: &one, MaxUnavailable: &half}},
+		{name: "no limit", pdb: &addonsv1alpha1.AddonPDB{}},
+		{name: "invalid percentage", pdb: &addonsv1alpha1.AddonPDB{MinAvailable: &invalid}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validatePDB(test.pdb)
+			if test.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func newTestAddonWithPDB() *addonsv1alpha1.Addon {
+	minAvailable := intstr.FromInt(1)
+
+	addon := testutil.NewAddon()
+	addon.Spec.PDB = &addonsv1alpha1.AddonPDB{
+		Selector: metav1.LabelSelector{
+			MatchLabels: map[string]string{"app": "manager"},
+		},
+		MinAvailable: &minAvailable,
+	}
+	return addon
+}
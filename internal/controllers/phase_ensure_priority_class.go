@@ -0,0 +1,219 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+// Priority values above are reserved for system critical Pods.
+const highestUserDefinablePriority = int32(1000000000)
+
+type ensurePriorityClassResult int
+
+const (
+	ensurePriorityClassResultNil   ensurePriorityClassResult = iota
+	ensurePriorityClassResultStop  ensurePriorityClassResult = iota
+	ensurePriorityClassResultRetry ensurePriorityClassResult = iota
+)
+
+// Ensures the PriorityClass referenced by the given Addon resource exists
+// and the cleanup of PriorityClasses that are not needed anymore.
+// returns an ensurePriorityClassResult that signals the caller if they have to
+// stop or retry reconciliation of the surrounding Addon resource
+func (r *AddonReconciler) ensurePriorityClass(
+	ctx context.Context, addon *addonsv1alpha1.Addon,
+) (ensurePriorityClassResult, error) {
+	var wantedPriorityClassName string
+	if addon.Spec.PriorityClass != nil {
+		if err := validatePriorityClass(addon.Spec.PriorityClass); err != nil {
+			// invalid configuration
+			return ensurePriorityClassResultStop, r.reportConfigurationError(ctx, addon,
+				fmt.Sprintf(".spec.priorityClass is invalid: %s", err))
+		}
+		wantedPriorityClassName = addon.Spec.PriorityClass.Name
+	}
+
+	// Ensure cleanup of PriorityClasses that were previously created for this Addon,
+	// also when the referenced PriorityClass is not managed by us (anymore)
+	if err := r.ensureDeletionOfUnwantedPriorityClasses(ctx, addon, wantedPriorityClassName); err != nil {
+		return ensurePriorityClassResultNil, err
+	}
+
+	if addon.Spec.PriorityClass == nil {
+		return ensurePriorityClassResultNil, nil
+	}
+	if addon.Spec.PriorityClass.Value == nil {
+		// PriorityClass is not managed by us, it just has to exist
+		return r.observePriorityClass(ctx, addon)
+	}
+
+	desiredPriorityClass := &schedulingv1.PriorityClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   addon.Spec.PriorityClass.Name,
+			Labels: map[string]string{},
+		},
+		Value:       *addon.Spec.PriorityClass.Value,
+		Description: fmt.Sprintf("PriorityClass of Addon %s", addon.Name),
+	}
+
+	addCommonLabels(desiredPriorityClass.Labels, addon)
+	if err := controllerutil.SetControllerReference(addon, desiredPriorityClass, r.Scheme); err != nil {
+		return ensurePriorityClassResultNil, fmt.Errorf("setting controller reference: %w", err)
+	}
+
+	err := r.reconcilePriorityClass(ctx, addon, desiredPriorityClass)
+	if errors.Is(err, errNotOwnedByUs) {
+		return ensurePriorityClassResultRetry, r.reportPriorityClassCollision(ctx, addon)
+	}
+	if err != nil {
+		return ensurePriorityClassResultNil, fmt.Errorf("reconciling PriorityClass: %w", err)
+	}
+	return ensurePriorityClassResultNil, nil
+}
+
+// Deletes all PriorityClasses that were created for the given Addon,
+// except for the PriorityClass with the given name.
+func (r *AddonReconciler) ensureDeletionOfUnwantedPriorityClasses(
+	ctx context.Context, addon *addonsv1alpha1.Addon, wantedPriorityClassName string,
+) error {
+	priorityClasses := &schedulingv1.PriorityClassList{}
+	if err := r.List(ctx, priorityClasses,
+		client.MatchingLabelsSelector{
+			Selector: commonLabelsAsLabelSelector(addon),
+		}); err != nil {
+		return fmt.Errorf("could not list owned PriorityClasses: %w", err)
+	}
+	for i := range priorityClasses.Items {
+		priorityClass := &priorityClasses.Items[i]
+		if priorityClass.Name == wantedPriorityClassName {
+			continue
+		}
+
+		err := r.Delete(ctx, priorityClass)
+		// don't propagate error if the PriorityClass is already gone
+		if err != nil && !k8sApiErrors.IsNotFound(err) {
+			return fmt.Errorf("could not delete PriorityClass: %w", err)
+		}
+	}
+	return nil
+}
+
+// Marks the Addon as unavailable, because the PriorityClass it wants to manage
+// already exists and is not controlled by it.
+func (r *AddonReconciler) reportPriorityClassCollision(
+	ctx context.Context, addon *addonsv1alpha1.Addon) error {
+	meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+		Type:   addonsv1alpha1.Available,
+		Status: metav1.ConditionFalse,
+		Reason: "CollidedPriorityClass",
+		Message: fmt.Sprintf(
+			"PriorityClass %q already exists and is not managed by this Addon",
+			addon.Spec.PriorityClass.Name),
+		ObservedGeneration: addon.Generation,
+	})
+	addon.Status.ObservedGeneration = addon.Generation
+	addon.Status.Phase = addonsv1alpha1.PhasePending
+	return r.updateAddonStatus(ctx, addon)
+}
+
+// Checks that the PriorityClass referenced by the given Addon exists
+// and reports the Addon as unavailable if it does not.
+func (r *AddonReconciler) observePriorityClass(
+	ctx context.Context, addon *addonsv1alpha1.Addon,
+) (ensurePriorityClassResult, error) {
+	priorityClass := &schedulingv1.PriorityClass{}
+	err := r.Get(ctx, client.ObjectKey{
+		Name: addon.Spec.PriorityClass.Name,
+	}, priorityClass)
+	if err == nil {
+		return ensurePriorityClassResultNil, nil
+	}
+	if !k8sApiErrors.IsNotFound(err) {
+		return ensurePriorityClassResultNil, fmt.Errorf("getting PriorityClass: %w", err)
+	}
+
+	// Pods referencing a missing PriorityClass are rejected on admission
+	meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+		Type:   addonsv1alpha1.Available,
+		Status: metav1.ConditionFalse,
+		Reason: "PriorityClassMissing",
+		Message: fmt.Sprintf(
+			"PriorityClass %q does not exist", addon.Spec.PriorityClass.Name),
+		ObservedGeneration: addon.Generation,
+	})
+	addon.Status.ObservedGeneration = addon.Generation
+	addon.Status.Phase = addonsv1alpha1.PhasePending
+	return ensurePriorityClassResultRetry, r.updateAddonStatus(ctx, addon)
+}
+
+// Reconciles the value of the given PriorityClass
+// by creating or re-creating the PriorityClass if needed.
+// PriorityClasses not controlled by the given Addon are left untouched
+// and errNotOwnedByUs is returned.
+func (r *AddonReconciler) reconcilePriorityClass(
+	ctx context.Context, addon *addonsv1alpha1.Addon,
+	priorityClass *schedulingv1.PriorityClass,
+) error {
+	currentPriorityClass := &schedulingv1.PriorityClass{}
+
+	err := r.Get(ctx, client.ObjectKeyFromObject(priorityClass), currentPriorityClass)
+	if k8sApiErrors.IsNotFound(err) {
+		return r.Create(ctx, priorityClass)
+	}
+	if err != nil {
+		return fmt.Errorf("getting PriorityClass: %w", err)
+	}
+
+	if !metav1.IsControlledBy(currentPriorityClass, addon) {
+		// PriorityClasses are cluster scoped and might be shared with others
+		return errNotOwnedByUs
+	}
+
+	if currentPriorityClass.Value != priorityClass.Value {
+		// value is immutable
+		if err := r.Delete(ctx, currentPriorityClass); err != nil &&
+			!k8sApiErrors.IsNotFound(err) {
+			return fmt.Errorf("deleting PriorityClass: %w", err)
+		}
+		return r.Create(ctx, priorityClass)
+	}
+	if currentPriorityClass.Description != priorityClass.Description {
+		currentPriorityClass.Description = priorityClass.Description
+		return r.Update(ctx, currentPriorityClass)
+	}
+	return nil
+}
+
+// Validates the name of the given PriorityClass
+// and that it can be created, if it is managed for the Addon.
+func validatePriorityClass(priorityClass *addonsv1alpha1.AddonPriorityClass) error {
+	if errs := validation.IsDNS1123Subdomain(priorityClass.Name); len(errs) > 0 {
+		return fmt.Errorf("invalid name %q: %s",
+			priorityClass.Name, strings.Join(errs, ", "))
+	}
+	if priorityClass.Value == nil {
+		return nil
+	}
+
+	// the "system-" prefix is reserved for built-in classes
+	if strings.HasPrefix(priorityClass.Name, "system-") {
+		return fmt.Errorf("name %q uses the reserved prefix \"system-\"", priorityClass.Name)
+	}
+	if *priorityClass.Value > highestUserDefinablePriority {
+		return fmt.Errorf("value %d exceeds the highest user definable priority %d",
+			*priorityClass.Value, highestUserDefinablePriority)
+	}
+	return nil
+}
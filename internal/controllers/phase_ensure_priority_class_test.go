@@ -0,0 +1,286 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestEnsurePriorityClass(t *testing.T) {
+	t.Run("existing class", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithCatalogSourceImage()
+		addon.Spec.PriorityClass = &addonsv1alpha1.AddonPriorityClass{
+			Name: "addon-critical",
+		}
+
+		c.On("Get", mock.Anything, client.ObjectKey{Name: "addon-critical"},
+			mock.IsType(&schedulingv1.PriorityClass{})).
+			Return(nil)
+		// class previously managed for the Addon
+		c.On("List", mock.Anything, mock.IsType(&schedulingv1.PriorityClassList{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				list := args.Get(1).(*schedulingv1.PriorityClassList)
+				list.Items = []schedulingv1.PriorityClass{
+					{ObjectMeta: metav1.ObjectMeta{Name: "addon-old"}},
+				}
+			}).
+			Return(nil)
+		c.On("Delete", mock.Anything, mock.IsType(&schedulingv1.PriorityClass{}), mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		result, err := r.ensurePriorityClass(ctx, addon)
+		require.NoError(t, err)
+		assert.Equal(t, ensurePriorityClassResultNil, result)
+
+		c.AssertExpectations(t)
+		c.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+		c.AssertCalled(t, "Delete", mock.Anything,
+			mock.MatchedBy(func(pc *schedulingv1.PriorityClass) bool {
+				return pc.Name == "addon-old"
+			}), mock.Anything)
+		c.StatusMock.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("creates class from spec", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithCatalogSourceImage()
+		addon.Spec.PriorityClass = &addonsv1alpha1.AddonPriorityClass{
+			Name:  "addon-critical",
+			Value: pointer.Int32Ptr(1000),
+		}
+
+		c.On("Get", mock.Anything, client.ObjectKey{Name: "addon-critical"},
+			mock.IsType(&schedulingv1.PriorityClass{})).
+			Return(newTestErrNotFound())
+		var createdPriorityClass *schedulingv1.PriorityClass
+		c.On("Create", mock.Anything, mock.IsType(&schedulingv1.PriorityClass{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				createdPriorityClass = args.Get(1).(*schedulingv1.PriorityClass)
+			}).
+			Return(nil)
+		c.On("List", mock.Anything, mock.IsType(&schedulingv1.PriorityClassList{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				list := args.Get(1).(*schedulingv1.PriorityClassList)
+				list.Items = []schedulingv1.PriorityClass{
+					{ObjectMeta: metav1.ObjectMeta{Name: "addon-critical"}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "addon-old"}},
+				}
+			}).
+			Return(nil)
+		c.On("Delete", mock.Anything, mock.IsType(&schedulingv1.PriorityClass{}), mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		result, err := r.ensurePriorityClass(ctx, addon)
+		require.NoError(t, err)
+		assert.Equal(t, ensurePriorityClassResultNil, result)
+
+		c.AssertExpectations(t)
+		if assert.NotNil(t, createdPriorityClass) {
+			assert.Equal(t, int32(1000), createdPriorityClass.Value)
+			assert.Equal(t, addon.Name, createdPriorityClass.Labels[commonInstanceLabel])
+			assert.True(t, metav1.IsControlledBy(createdPriorityClass, addon))
+		}
+		// only the class no longer in the spec is removed
+		c.AssertNumberOfCalls(t, "Delete", 1)
+		c.AssertCalled(t, "Delete", mock.Anything,
+			mock.MatchedBy(func(pc *schedulingv1.PriorityClass) bool {
+				return pc.Name == "addon-old"
+			}), mock.Anything)
+	})
+
+	t.Run("missing class", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithCatalogSourceImage()
+		addon.Spec.PriorityClass = &addonsv1alpha1.AddonPriorityClass{
+			Name: "addon-critical",
+		}
+
+		c.On("Get", mock.Anything, client.ObjectKey{Name: "addon-critical"},
+			mock.IsType(&schedulingv1.PriorityClass{})).
+			Return(newTestErrNotFound())
+		c.On("List", mock.Anything, mock.IsType(&schedulingv1.PriorityClassList{}), mock.Anything).
+			Return(nil)
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		result, err := r.ensurePriorityClass(ctx, addon)
+		require.NoError(t, err)
+		assert.Equal(t, ensurePriorityClassResultRetry, result)
+
+		c.StatusMock.AssertCalled(t, "Update", mock.Anything, addon, mock.Anything)
+		availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+		if assert.NotNil(t, availableCond) {
+			assert.Equal(t, metav1.ConditionFalse, availableCond.Status)
+			assert.Equal(t, "PriorityClassMissing", availableCond.Reason)
+		}
+	})
+
+	t.Run("collision with foreign class", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithCatalogSourceImage()
+		value := int32(1000)
+		addon.Spec.PriorityClass = &addonsv1alpha1.AddonPriorityClass{
+			Name:  "addon-critical",
+			Value: &value,
+		}
+
+		c.On("List", mock.Anything, mock.IsType(&schedulingv1.PriorityClassList{}), mock.Anything).
+			Return(nil)
+		c.On("Get", mock.Anything, client.ObjectKey{Name: "addon-critical"},
+			mock.IsType(&schedulingv1.PriorityClass{})).
+			Run(func(args mock.Arguments) {
+				pc := args.Get(2).(*schedulingv1.PriorityClass)
+				pc.Name = "addon-critical"
+				pc.Value = 2000
+			}).
+			Return(nil)
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		result, err := r.ensurePriorityClass(ctx, addon)
+		require.NoError(t, err)
+		assert.Equal(t, ensurePriorityClassResultRetry, result)
+
+		c.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+		availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+		if assert.NotNil(t, availableCond) {
+			assert.Equal(t, metav1.ConditionFalse, availableCond.Status)
+			assert.Equal(t, "CollidedPriorityClass", availableCond.Reason)
+			assert.Contains(t, availableCond.Message, "addon-critical")
+		}
+	})
+
+	t.Run("rejects reserved name", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithCatalogSourceImage()
+		addon.Spec.PriorityClass = &addonsv1alpha1.AddonPriorityClass{
+			Name:  "system-addon-critical",
+			Value: pointer.Int32Ptr(1000),
+		}
+
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		result, err := r.ensurePriorityClass(ctx, addon)
+		require.NoError(t, err)
+		assert.Equal(t, ensurePriorityClassResultStop, result)
+
+		c.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+		availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+		if assert.NotNil(t, availableCond) {
+			assert.Equal(t, "ConfigurationError", availableCond.Reason)
+		}
+	})
+}
+
+func TestReconcilePriorityClass(t *testing.T) {
+	addon := newTestAddonWithCatalogSourceImage()
+
+	newPriorityClass := func(value int32) *schedulingv1.PriorityClass {
+		pc := &schedulingv1.PriorityClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "addon-critical"},
+			Value:      value,
+		}
+		require.NoError(t, controllerutil.SetControllerReference(addon, pc, newTestSchemeWithAddonsv1alpha1()))
+		return pc
+	}
+
+	t.Run("value change recreates class", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		c.On("Get", mock.Anything, client.ObjectKey{Name: "addon-critical"},
+			mock.IsType(&schedulingv1.PriorityClass{})).
+			Run(func(args mock.Arguments) {
+				newPriorityClass(1000).DeepCopyInto(args.Get(2).(*schedulingv1.PriorityClass))
+			}).
+			Return(nil)
+		c.On("Delete", mock.Anything, mock.IsType(&schedulingv1.PriorityClass{}), mock.Anything).
+			Return(nil)
+		c.On("Create", mock.Anything, mock.IsType(&schedulingv1.PriorityClass{}), mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.reconcilePriorityClass(ctx, addon, newPriorityClass(2000))
+		require.NoError(t, err)
+
+		c.AssertExpectations(t)
+		c.AssertCalled(t, "Create", mock.Anything,
+			mock.MatchedBy(func(pc *schedulingv1.PriorityClass) bool {
+				return pc.Value == 2000
+			}), mock.Anything)
+	})
+
+	t.Run("foreign class is left untouched", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		c.On("Get", mock.Anything, client.ObjectKey{Name: "addon-critical"},
+			mock.IsType(&schedulingv1.PriorityClass{})).
+			Run(func(args mock.Arguments) {
+				pc := args.Get(2).(*schedulingv1.PriorityClass)
+				pc.Name = "addon-critical"
+				pc.Value = 1000
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.reconcilePriorityClass(ctx, addon, newPriorityClass(2000))
+		require.Equal(t, errNotOwnedByUs, err)
+
+		c.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+		c.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
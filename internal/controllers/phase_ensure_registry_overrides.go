@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+const (
+	// Annotation on Deployments remembering the container images
+	// before registry overrides were applied, so they can be reverted.
+	originalImagesAnnotation = "addons.managed.openshift.io/original-images"
+
+	// Label OLM puts on objects it creates for a ClusterServiceVersion.
+	olmOwnerLabel = "olm.owner"
+)
+
+// Container image before and after registry overrides were applied.
+type overriddenImage struct {
+	Original   string `json:"original"`
+	Overridden string `json:"overridden"`
+}
+
+// Ensures the registry overrides of the given Addon are applied
+// to the Deployments of the given ClusterServiceVersion
+// and reverted, when they are removed from the Addon.
+// returns a bool that signals the caller to stop reconciliation
+func (r *AddonReconciler) ensureRegistryOverrides(
+	ctx context.Context, addon *addonsv1alpha1.Addon,
+	csvKey client.ObjectKey,
+) (stop bool, err error) {
+	if err := validateRegistryOverrides(addon.Spec.RegistryOverrides); err != nil {
+		// invalid configuration
+		return true, r.reportConfigurationError(ctx, addon,
+			fmt.Sprintf(".spec.registryOverrides is invalid: %s", err))
+	}
+
+	deployments := &appsv1.DeploymentList{}
+	if err := r.apiReader().List(ctx, deployments,
+		client.InNamespace(csvKey.Namespace),
+		client.MatchingLabels{
+			olmOwnerLabel: csvKey.Name,
+		}); err != nil {
+		return false, fmt.Errorf("could not list Deployments of CSV: %w", err)
+	}
+
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		patchBase := client.MergeFrom(deployment.DeepCopy())
+		changed, err := applyRegistryOverrides(deployment, addon.Spec.RegistryOverrides)
+		if err != nil {
+			return false, fmt.Errorf("applying registry overrides to Deployment %s: %w",
+				deployment.Name, err)
+		}
+		if !changed {
+			continue
+		}
+
+		err = r.Patch(ctx, deployment, patchBase)
+		// don't propagate error if the Deployment is already gone
+		if err != nil && !k8sApiErrors.IsNotFound(err) {
+			return false, fmt.Errorf("patching Deployment: %w", err)
+		}
+	}
+
+	return false, nil
+}
+
+// Applies the given registry overrides to all containers of the given Deployment
+// and records the original images in an annotation.
+// Images that were changed by someone else since they were overridden
+// are treated as the new original image.
+// returns true if the Deployment was changed
+func applyRegistryOverrides(
+	deployment *appsv1.Deployment,
+	overrides []addonsv1alpha1.AddonRegistryOverride,
+) (changed bool, err error) {
+	images := map[string]overriddenImage{}
+	if value, ok := deployment.Annotations[originalImagesAnnotation]; ok {
+		if err := json.Unmarshal([]byte(value), &images); err != nil {
+			return false, fmt.Errorf("parsing %s annotation: %w", originalImagesAnnotation, err)
+		}
+	}
+	previousImages := images
+
+	images = map[string]overriddenImage{}
+	podSpec := &deployment.Spec.Template.Spec
+	for _, containers := range [][]corev1.Container{
+		podSpec.InitContainers, podSpec.Containers,
+	} {
+		for i := range containers {
+			container := &containers[i]
+
+			original := container.Image
+			if previous, ok := previousImages[container.Name]; ok &&
+				previous.Overridden == container.Image {
+				original = previous.Original
+			}
+
+			overridden := overrideImageRegistry(original, overrides)
+			if overridden != original {
+				images[container.Name] = overriddenImage{
+					Original:   original,
+					Overridden: overridden,
+				}
+			}
+			if container.Image != overridden {
+				container.Image = overridden
+				changed = true
+			}
+		}
+	}
+
+	if equality.Semantic.DeepEqual(images, previousImages) {
+		return changed, nil
+	}
+	if len(images) == 0 {
+		delete(deployment.Annotations, originalImagesAnnotation)
+		return true, nil
+	}
+
+	value, err := json.Marshal(images)
+	if err != nil {
+		return false, fmt.Errorf("serializing %s annotation: %w", originalImagesAnnotation, err)
+	}
+	if deployment.Annotations == nil {
+		deployment.Annotations = map[string]string{}
+	}
+	deployment.Annotations[originalImagesAnnotation] = string(value)
+	return true, nil
+}
+
+// Replaces the registry of the given image with the mirror of the first matching override.
+func overrideImageRegistry(
+	image string, overrides []addonsv1alpha1.AddonRegistryOverride) string {
+	for _, override := range overrides {
+		// only match on path boundaries, so quay.io does not match quay.io.example.com
+		if strings.HasPrefix(image, override.Source+"/") {
+			return override.Mirror + strings.TrimPrefix(image, override.Source)
+		}
+	}
+	return image
+}
+
+// Validates that sources and mirrors are plain registry or repository prefixes.
+func validateRegistryOverrides(overrides []addonsv1alpha1.AddonRegistryOverride) error {
+	for _, override := range overrides {
+		for _, prefix := range []string{override.Source, override.Mirror} {
+			if len(prefix) == 0 ||
+				strings.ContainsAny(prefix, " \t\n@") ||
+				strings.Contains(prefix, "://") ||
+				strings.HasSuffix(prefix, "/") {
+				return fmt.Errorf("%q is not a valid registry", prefix)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestEnsureRegistryOverrides(t *testing.T) {
+	c := testutil.NewClient()
+	r := &AddonReconciler{
+		Client: c,
+		Scheme: newTestSchemeWithAddonsv1alpha1(),
+	}
+
+	addon := newTestAddonWithCatalogSourceImage()
+	addon.Spec.RegistryOverrides = []addonsv1alpha1.AddonRegistryOverride{
+		{Source: "quay.io", Mirror: "mirror.example.com:5000"},
+	}
+	csvKey := client.ObjectKey{Name: "addon-1.v1.0.0", Namespace: "addon-1"}
+
+	c.On("List", mock.Anything, mock.IsType(&appsv1.DeploymentList{}), mock.Anything).
+		Run(func(args mock.Arguments) {
+			list := args.Get(1).(*appsv1.DeploymentList)
+			list.Items = []appsv1.Deployment{
+				*newTestDeploymentWithImage("manager", "quay.io/osd-addons/manager:v1"),
+				*newTestDeploymentWithImage("proxy", "registry.redhat.io/ubi8/proxy:v1"),
+			}
+		}).
+		Return(nil)
+	var patchedDeployments []*appsv1.Deployment
+	c.On("Patch", mock.Anything, mock.IsType(&appsv1.Deployment{}), mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			patchedDeployments = append(patchedDeployments, args.Get(1).(*appsv1.Deployment))
+		}).
+		Return(nil)
+
+	ctx := context.Background()
+	stop, err := r.ensureRegistryOverrides(ctx, addon, csvKey)
+	require.NoError(t, err)
+	assert.False(t, stop)
+
+	c.AssertCalled(t, "List", mock.Anything, mock.Anything,
+		[]client.ListOption{
+			client.InNamespace("addon-1"),
+			client.MatchingLabels{olmOwnerLabel: "addon-1.v1.0.0"},
+		})
+	// Deployments without matching images are not patched
+	if assert.Len(t, patchedDeployments, 1) {
+		assert.Equal(t, "manager", patchedDeployments[0].Name)
+		assert.Equal(t, "mirror.example.com:5000/osd-addons/manager:v1",
+			patchedDeployments[0].Spec.Template.Spec.Containers[0].Image)
+	}
+}
+
+func TestApplyRegistryOverrides(t *testing.T) {
+	quayMirror := []addonsv1alpha1.AddonRegistryOverride{
+		{Source: "quay.io", Mirror: "mirror.example.com:5000"},
+	}
+
+	t.Run("applies override", func(t *testing.T) {
+		deployment := newTestDeploymentWithImage("manager", "quay.io/osd-addons/manager:v1")
+
+		changed, err := applyRegistryOverrides(deployment, quayMirror)
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, "mirror.example.com:5000/osd-addons/manager:v1",
+			deployment.Spec.Template.Spec.Containers[0].Image)
+		assert.Contains(t, deployment.Annotations[originalImagesAnnotation],
+			`"original":"quay.io/osd-addons/manager:v1"`)
+
+		// applying again is a no-op
+		changed, err = applyRegistryOverrides(deployment, quayMirror)
+		require.NoError(t, err)
+		assert.False(t, changed)
+	})
+
+	t.Run("updates override", func(t *testing.T) {
+		deployment := newTestDeploymentWithImage("manager", "quay.io/osd-addons/manager:v1")
+		_, err := applyRegistryOverrides(deployment, quayMirror)
+		require.NoError(t, err)
+
+		changed, err := applyRegistryOverrides(deployment, []addonsv1alpha1.AddonRegistryOverride{
+			{Source: "quay.io/osd-addons", Mirror: "other-mirror.example.com/addons"},
+		})
+		require.NoError(t, err)
+		assert.True(t, changed)
+		// the new override is applied to the original image
+		assert.Equal(t, "other-mirror.example.com/addons/manager:v1",
+			deployment.Spec.Template.Spec.Containers[0].Image)
+	})
+
+	t.Run("reverts on removal", func(t *testing.T) {
+		deployment := newTestDeploymentWithImage("manager", "quay.io/osd-addons/manager:v1")
+		_, err := applyRegistryOverrides(deployment, quayMirror)
+		require.NoError(t, err)
+
+		changed, err := applyRegistryOverrides(deployment, nil)
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, "quay.io/osd-addons/manager:v1",
+			deployment.Spec.Template.Spec.Containers[0].Image)
+		assert.NotContains(t, deployment.Annotations, originalImagesAnnotation)
+	})
+
+	t.Run("image changed by someone else", func(t *testing.T) {
+		deployment := newTestDeploymentWithImage("manager", "quay.io/osd-addons/manager:v1")
+		_, err := applyRegistryOverrides(deployment, quayMirror)
+		require.NoError(t, err)
+
+		// e.g. OLM rolled out a new version
+		deployment.Spec.Template.Spec.Containers[0].Image = "quay.io/osd-addons/manager:v2"
+
+		_, err = applyRegistryOverrides(deployment, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "quay.io/osd-addons/manager:v2",
+			deployment.Spec.Template.Spec.Containers[0].Image)
+	})
+}
+
+func TestOverrideImageRegistry(t *testing.T) {
+	overrides := []addonsv1alpha1.AddonRegistryOverride{
+		{Source: "quay.io", Mirror: "mirror.example.com"},
+	}
+
+	assert.Equal(t, "mirror.example.com/osd-addons/manager@sha256:abc",
+		overrideImageRegistry("quay.io/osd-addons/manager@sha256:abc", overrides))
+	// no partial registry matches
+	assert.Equal(t, "quay.io.example.com/manager:v1",
+		overrideImageRegistry("quay.io.example.com/manager:v1", overrides))
+}
+
+func TestValidateRegistryOverrides(t *testing.T) {
+	assert.NoError(t, validateRegistryOverrides([]addonsv1alpha1.AddonRegistryOverride{
+		{Source: "quay.io/osd-addons", Mirror: "mirror.example.com:5000"},
+	}))
+
+	for _, invalid := range []string{"", "https://quay.io", "quay.io/", "quay .io"} {
+		assert.Error(t, validateRegistryOverrides([]addonsv1alpha1.AddonRegistryOverride{
+			{Source: invalid, Mirror: "mirror.example.com"},
+		}), invalid)
+	}
+}
+
+func newTestDeploymentWithImage(name, image string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "addon-1",
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: name, Image: image},
+					},
+				},
+			},
+		},
+	}
+}
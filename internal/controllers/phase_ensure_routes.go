@@ -0,0 +1,223 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	toolscache "k8s.io/client-go/tools/cache"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+// The OpenShift route API is not vendored,
+// so Routes are handled as unstructured objects.
+var routeGVK = schema.GroupVersionKind{
+	Group:   "route.openshift.io",
+	Version: "v1",
+	Kind:    "Route",
+}
+
+// Creates an informer watching Routes created for Addons,
+// returns nil if the route API is not available on this cluster.
+func newRouteInformer(mgr ctrl.Manager) (toolscache.SharedIndexInformer, error) {
+	mapping, err := mgr.GetRESTMapper().RESTMapping(routeGVK.GroupKind(), routeGVK.Version)
+	if meta.IsNoMatchError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting REST mapping for Routes: %w", err)
+	}
+	return newCommonLabelInformer(mgr, mapping.Resource)
+}
+
+// Ensures the Routes specified in the given Addon resource
+// and the cleanup of Routes that are not needed anymore.
+// returns a bool that signals the caller to stop reconciliation
+func (r *AddonReconciler) ensureRoutes(
+	ctx context.Context, addon *addonsv1alpha1.Addon) (stop bool, err error) {
+	targetNamespace := getCommonInstallOptions(addon).Namespace
+
+	for _, route := range addon.Spec.Routes {
+		if err := validateRoute(route); err != nil {
+			// invalid configuration
+			return true, r.reportConfigurationError(ctx, addon,
+				fmt.Sprintf(".spec.routes[%s] is invalid: %s", route.Name, err))
+		}
+	}
+
+	wantedRouteNames := map[string]struct{}{}
+	for _, route := range addon.Spec.Routes {
+		desiredRoute := newRoute(targetNamespace, route)
+		labels := map[string]string{}
+		addCommonLabels(labels, addon)
+		desiredRoute.SetLabels(labels)
+		if err := controllerutil.SetControllerReference(addon, desiredRoute, r.Scheme); err != nil {
+			return false, fmt.Errorf("setting controller reference: %w", err)
+		}
+
+		err := r.reconcileRoute(ctx, desiredRoute)
+		if meta.IsNoMatchError(err) {
+			// not an OpenShift cluster
+			return true, r.reportConfigurationError(ctx, addon,
+				".spec.routes requires Routes, which are not available on this cluster")
+		}
+		if err != nil {
+			return false, fmt.Errorf("reconciling Route: %w", err)
+		}
+		wantedRouteNames[desiredRoute.GetName()] = struct{}{}
+	}
+
+	// Ensure cleanup of Routes that were previously created for this Addon
+	routes := &unstructured.UnstructuredList{}
+	routes.SetGroupVersionKind(routeGVK.GroupVersion().WithKind(routeGVK.Kind + "List"))
+	err = r.List(ctx, routes,
+		client.InNamespace(targetNamespace),
+		client.MatchingLabelsSelector{
+			Selector: commonLabelsAsLabelSelector(addon),
+		})
+	if meta.IsNoMatchError(err) {
+		// not an OpenShift cluster, so there is nothing to clean up
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("could not list owned Routes: %w", err)
+	}
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		if _, ok := wantedRouteNames[route.GetName()]; ok {
+			continue
+		}
+
+		err := r.Delete(ctx, route)
+		// don't propagate error if the Route is already gone
+		if err != nil && !k8sApiErrors.IsNotFound(err) {
+			return false, fmt.Errorf("could not delete Route: %w", err)
+		}
+	}
+
+	return false, nil
+}
+
+// Reconciles the host, backend and TLS configuration of the given Route
+// by creating or updating the Route if needed.
+func (r *AddonReconciler) reconcileRoute(
+	ctx context.Context, route *unstructured.Unstructured) error {
+	currentRoute := &unstructured.Unstructured{}
+	currentRoute.SetGroupVersionKind(routeGVK)
+
+	err := r.Get(ctx, client.ObjectKeyFromObject(route), currentRoute)
+	if k8sApiErrors.IsNotFound(err) {
+		return r.Create(ctx, route)
+	}
+	if meta.IsNoMatchError(err) {
+		// not wrapped, so the caller can detect the missing API
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("getting Route: %w", err)
+	}
+
+	fields := []string{"to", "port", "tls"}
+	// a host generated by the router is not drift
+	if _, ok, _ := unstructured.NestedString(route.Object, "spec", "host"); ok {
+		fields = append(fields, "host")
+	}
+
+	var changed bool
+	for _, field := range fields {
+		wanted, wantedOk, _ := unstructured.NestedFieldNoCopy(route.Object, "spec", field)
+		current, currentOk, _ := unstructured.NestedFieldNoCopy(currentRoute.Object, "spec", field)
+		if wantedOk == currentOk && equality.Semantic.DeepEqual(current, wanted) {
+			continue
+		}
+
+		if !wantedOk {
+			unstructured.RemoveNestedField(currentRoute.Object, "spec", field)
+		} else if err := unstructured.SetNestedField(
+			currentRoute.Object, wanted, "spec", field); err != nil {
+			return fmt.Errorf("setting .spec.%s: %w", field, err)
+		}
+		changed = true
+	}
+	if changed {
+		return r.Update(ctx, currentRoute)
+	}
+	return nil
+}
+
+// Builds a Route routing traffic to the Service referenced by the given AddonRoute.
+func newRoute(namespace string, route addonsv1alpha1.AddonRoute) *unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"to": map[string]interface{}{
+			"kind": "Service",
+			"name": route.ServiceName,
+			// explicitly default, so the spec can be compared to the defaulted object
+			"weight": int64(100),
+		},
+	}
+	if len(route.Host) > 0 {
+		spec["host"] = route.Host
+	}
+	if route.TargetPort != nil {
+		var targetPort interface{} = route.TargetPort.StrVal
+		if route.TargetPort.Type == intstr.Int {
+			targetPort = int64(route.TargetPort.IntVal)
+		}
+		spec["port"] = map[string]interface{}{
+			"targetPort": targetPort,
+		}
+	}
+	if route.TLS != nil {
+		tls := map[string]interface{}{
+			"termination": route.TLS.Termination,
+		}
+		if len(route.TLS.InsecureEdgeTerminationPolicy) > 0 {
+			tls["insecureEdgeTerminationPolicy"] = route.TLS.InsecureEdgeTerminationPolicy
+		}
+		spec["tls"] = tls
+	}
+
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": spec,
+		},
+	}
+	obj.SetGroupVersionKind(routeGVK)
+	obj.SetName(route.Name)
+	obj.SetNamespace(namespace)
+	return obj
+}
+
+// Validates the name, host and TLS configuration of the given AddonRoute.
+func validateRoute(route addonsv1alpha1.AddonRoute) error {
+	if errs := validation.IsDNS1123Subdomain(route.Name); len(errs) > 0 {
+		return fmt.Errorf("invalid name %q: %s",
+			route.Name, strings.Join(errs, ", "))
+	}
+	if len(route.Host) > 0 {
+		if errs := validation.IsDNS1123Subdomain(route.Host); len(errs) > 0 {
+			return fmt.Errorf("invalid host %q: %s",
+				route.Host, strings.Join(errs, ", "))
+		}
+	}
+	// the router never sees decrypted traffic of passthrough Routes,
+	// so it can't serve them over plain HTTP
+	if route.TLS != nil &&
+		route.TLS.Termination == "passthrough" &&
+		route.TLS.InsecureEdgeTerminationPolicy == "Allow" {
+		return fmt.Errorf(
+			"insecureEdgeTerminationPolicy Allow is not supported with passthrough termination")
+	}
+	return nil
+}
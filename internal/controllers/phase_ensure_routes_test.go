@@ -0,0 +1,204 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestEnsureRoutes(t *testing.T) {
+	t.Run("creates Route", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithRoute()
+
+		c.ExpectNotFound(client.ObjectKey{
+			Name:      "console",
+			Namespace: "addon-1",
+		}, &unstructured.Unstructured{})
+		createdRoute := &unstructured.Unstructured{}
+		c.ExpectCreate(createdRoute)
+		c.ExpectList(&unstructured.UnstructuredList{})
+
+		ctx := context.Background()
+		stop, err := r.ensureRoutes(ctx, addon)
+		require.NoError(t, err)
+		assert.False(t, stop)
+
+		c.AssertExpectations(t)
+		assert.Equal(t, routeGVK, createdRoute.GroupVersionKind())
+		serviceName, _, _ := unstructured.NestedString(createdRoute.Object, "spec", "to", "name")
+		assert.Equal(t, "console", serviceName)
+		targetPort, _, _ := unstructured.NestedString(createdRoute.Object, "spec", "port", "targetPort")
+		assert.Equal(t, "https", targetPort)
+		termination, _, _ := unstructured.NestedString(createdRoute.Object, "spec", "tls", "termination")
+		assert.Equal(t, "reencrypt", termination)
+		_, hasHost, _ := unstructured.NestedString(createdRoute.Object, "spec", "host")
+		assert.False(t, hasHost)
+		assert.Equal(t, addon.Name, createdRoute.GetLabels()[commonInstanceLabel])
+	})
+
+	t.Run("deletes Route removed from spec", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithRoute()
+		addon.Spec.Routes = nil
+
+		c.ExpectList(&unstructured.UnstructuredList{},
+			newRoute("addon-1", newTestAddonWithRoute().Spec.Routes[0]))
+		c.ExpectDelete(&unstructured.Unstructured{})
+
+		ctx := context.Background()
+		stop, err := r.ensureRoutes(ctx, addon)
+		require.NoError(t, err)
+		assert.False(t, stop)
+
+		c.AssertExpectations(t)
+		c.AssertCalled(t, "Delete", mock.Anything,
+			mock.MatchedBy(func(route *unstructured.Unstructured) bool {
+				return route.GetName() == "console" && route.GetNamespace() == "addon-1"
+			}), mock.Anything)
+	})
+
+	t.Run("rejects invalid TLS config", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithRoute()
+		addon.Spec.Routes[0].TLS = &addonsv1alpha1.AddonRouteTLS{
+			Termination:                   "passthrough",
+			InsecureEdgeTerminationPolicy: "Allow",
+		}
+
+		c.ExpectStatusUpdate()
+
+		ctx := context.Background()
+		stop, err := r.ensureRoutes(ctx, addon)
+		require.NoError(t, err)
+		assert.True(t, stop)
+
+		c.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+		availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+		if assert.NotNil(t, availableCond) {
+			assert.Equal(t, metav1.ConditionFalse, availableCond.Status)
+			assert.Equal(t, "ConfigurationError", availableCond.Reason)
+			assert.Contains(t, availableCond.Message, "passthrough")
+		}
+	})
+
+	t.Run("reports missing API", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithRoute()
+
+		c.On("Get", mock.Anything, mock.Anything, mock.IsType(&unstructured.Unstructured{})).
+			Return(&meta.NoKindMatchError{
+				GroupKind: routeGVK.GroupKind(),
+			})
+		c.ExpectStatusUpdate()
+
+		ctx := context.Background()
+		stop, err := r.ensureRoutes(ctx, addon)
+		require.NoError(t, err)
+		assert.True(t, stop)
+
+		c.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+		availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+		if assert.NotNil(t, availableCond) {
+			assert.Equal(t, metav1.ConditionFalse, availableCond.Status)
+			assert.Equal(t, "ConfigurationError", availableCond.Reason)
+			assert.Contains(t, availableCond.Message, "Routes")
+		}
+	})
+}
+
+func TestReconcileRoute(t *testing.T) {
+	route := newRoute("addon-1", newTestAddonWithRoute().Spec.Routes[0])
+
+	t.Run("corrects TLS drift and keeps generated host", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		currentRoute := route.DeepCopy()
+		currentRoute.SetResourceVersion("123")
+		_ = unstructured.SetNestedField(currentRoute.Object,
+			"console-addon-1.apps.example.com", "spec", "host")
+		unstructured.RemoveNestedField(currentRoute.Object, "spec", "tls")
+		c.ExpectGet(currentRoute)
+		updatedRoute := &unstructured.Unstructured{}
+		c.ExpectUpdate(updatedRoute)
+
+		ctx := context.Background()
+		err := r.reconcileRoute(ctx, route.DeepCopy())
+		require.NoError(t, err)
+
+		termination, _, _ := unstructured.NestedString(updatedRoute.Object, "spec", "tls", "termination")
+		assert.Equal(t, "reencrypt", termination)
+		host, _, _ := unstructured.NestedString(updatedRoute.Object, "spec", "host")
+		assert.Equal(t, "console-addon-1.apps.example.com", host)
+		// update has to be done on the existing object
+		assert.Equal(t, "123", updatedRoute.GetResourceVersion())
+	})
+
+	t.Run("no-op when spec matches", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		c.ExpectGet(route)
+
+		ctx := context.Background()
+		err := r.reconcileRoute(ctx, route.DeepCopy())
+		require.NoError(t, err)
+		c.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func newTestAddonWithRoute() *addonsv1alpha1.Addon {
+	targetPort := intstr.FromString("https")
+
+	addon := testutil.NewAddon()
+	addon.Spec.Routes = []addonsv1alpha1.AddonRoute{
+		{
+			Name:        "console",
+			ServiceName: "console",
+			TargetPort:  &targetPort,
+			TLS: &addonsv1alpha1.AddonRouteTLS{
+				Termination:                   "reencrypt",
+				InsecureEdgeTerminationPolicy: "Redirect",
+			},
+		},
+	}
+	return addon
+}
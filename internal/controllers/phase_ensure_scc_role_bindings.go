@@ -0,0 +1,160 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+// OpenShift ships a ClusterRole for every SecurityContextConstraint,
+// granting use of it: system:openshift:scc:<scc name>
+const sccClusterRolePrefix = "system:openshift:scc:"
+
+// Ensures RoleBindings granting the SecurityContextConstraints specified in the given Addon
+// to all ServiceAccounts of its install namespace and the cleanup of RoleBindings
+// that are not needed anymore.
+// returns a bool that signals the caller to stop reconciliation
+func (r *AddonReconciler) ensureSCCRoleBindings(
+	ctx context.Context, addon *addonsv1alpha1.Addon) (stop bool, err error) {
+	targetNamespace := getCommonInstallOptions(addon).Namespace
+
+	var sccNames []string
+	if addon.Spec.SCC != nil {
+		sccNames = addon.Spec.SCC.Names
+	}
+
+	// Check all SCCs before granting any of them,
+	// so we don't end up with a partially applied configuration.
+	for _, sccName := range sccNames {
+		allowed, err := r.canUseSCC(ctx, targetNamespace, sccName)
+		if err != nil {
+			return false, fmt.Errorf("checking permission to use SCC %s: %w", sccName, err)
+		}
+		if !allowed {
+			// granting an SCC the operator can't use itself would be a privilege escalation
+			return true, r.reportConfigurationError(ctx, addon,
+				fmt.Sprintf(".spec.scc.names: not allowed to grant SecurityContextConstraint %q", sccName))
+		}
+	}
+
+	wantedRoleBindingNames := map[string]struct{}{}
+	for _, sccName := range sccNames {
+		desiredRoleBinding := &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      sccRoleBindingName(addon, sccName),
+				Namespace: targetNamespace,
+				Labels:    map[string]string{},
+			},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "ClusterRole",
+				Name:     sccClusterRolePrefix + sccName,
+			},
+			Subjects: []rbacv1.Subject{
+				{
+					APIGroup: rbacv1.GroupName,
+					Kind:     rbacv1.GroupKind,
+					Name:     "system:serviceaccounts:" + targetNamespace,
+				},
+			},
+		}
+
+		addCommonLabels(desiredRoleBinding.Labels, addon)
+		if err := controllerutil.SetControllerReference(addon, desiredRoleBinding, r.Scheme); err != nil {
+			return false, fmt.Errorf("setting controller reference: %w", err)
+		}
+
+		if err := r.reconcileRoleBinding(ctx, desiredRoleBinding); err != nil {
+			return false, fmt.Errorf("reconciling RoleBinding: %w", err)
+		}
+		wantedRoleBindingNames[desiredRoleBinding.Name] = struct{}{}
+	}
+
+	// Ensure cleanup of RoleBindings that were previously created for this Addon
+	roleBindings := &rbacv1.RoleBindingList{}
+	if err := r.List(ctx, roleBindings,
+		client.InNamespace(targetNamespace),
+		client.MatchingLabelsSelector{
+			Selector: commonLabelsAsLabelSelector(addon),
+		}); err != nil {
+		return false, fmt.Errorf("could not list owned RoleBindings: %w", err)
+	}
+	for i := range roleBindings.Items {
+		roleBinding := &roleBindings.Items[i]
+		if _, ok := wantedRoleBindingNames[roleBinding.Name]; ok {
+			continue
+		}
+
+		err := r.Delete(ctx, roleBinding)
+		// don't propagate error if the RoleBinding is already gone
+		if err != nil && !k8sApiErrors.IsNotFound(err) {
+			return false, fmt.Errorf("could not delete RoleBinding: %w", err)
+		}
+	}
+
+	return false, nil
+}
+
+// Reconciles the RoleRef and Subjects of the given RoleBinding
+// by creating, updating or replacing the RoleBinding if needed.
+func (r *AddonReconciler) reconcileRoleBinding(
+	ctx context.Context, roleBinding *rbacv1.RoleBinding) error {
+	currentRoleBinding := &rbacv1.RoleBinding{}
+
+	err := r.Get(ctx, client.ObjectKeyFromObject(roleBinding), currentRoleBinding)
+	if k8sApiErrors.IsNotFound(err) {
+		return r.Create(ctx, roleBinding)
+	}
+	if err != nil {
+		return fmt.Errorf("getting RoleBinding: %w", err)
+	}
+
+	// roleRef is immutable, so the RoleBinding has to be replaced
+	if !equality.Semantic.DeepEqual(currentRoleBinding.RoleRef, roleBinding.RoleRef) {
+		if err := r.Delete(ctx, currentRoleBinding); err != nil &&
+			!k8sApiErrors.IsNotFound(err) {
+			return fmt.Errorf("deleting RoleBinding: %w", err)
+		}
+		return r.Create(ctx, roleBinding)
+	}
+
+	// only update when subjects have changed
+	if !equality.Semantic.DeepEqual(currentRoleBinding.Subjects, roleBinding.Subjects) {
+		currentRoleBinding.Subjects = roleBinding.Subjects
+		return r.Update(ctx, currentRoleBinding)
+	}
+	return nil
+}
+
+// Checks whether the operator itself is allowed to use the given SCC in the given namespace.
+func (r *AddonReconciler) canUseSCC(
+	ctx context.Context, namespace, sccName string) (bool, error) {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "use",
+				Group:     "security.openshift.io",
+				Resource:  "securitycontextconstraints",
+				Name:      sccName,
+			},
+		},
+	}
+	if err := r.Create(ctx, review); err != nil {
+		return false, fmt.Errorf("creating SelfSubjectAccessReview: %w", err)
+	}
+	return review.Status.Allowed, nil
+}
+
+func sccRoleBindingName(addon *addonsv1alpha1.Addon, sccName string) string {
+	return fmt.Sprintf("%s-scc-%s", addon.Name, sccName)
+}
@@ -0,0 +1,256 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestEnsureSCCRoleBindings(t *testing.T) {
+	t.Run("grants SCC", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithSCC()
+
+		var review *authorizationv1.SelfSubjectAccessReview
+		c.On("Create", mock.Anything, mock.IsType(&authorizationv1.SelfSubjectAccessReview{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				review = args.Get(1).(*authorizationv1.SelfSubjectAccessReview)
+				review.Status.Allowed = true
+			}).
+			Return(nil)
+		c.On("Get", mock.Anything, client.ObjectKey{
+			Name:      "addon-1-scc-anyuid",
+			Namespace: "addon-1",
+		}, mock.IsType(&rbacv1.RoleBinding{})).
+			Return(newTestErrNotFound())
+		var createdRoleBinding *rbacv1.RoleBinding
+		c.On("Create", mock.Anything, mock.IsType(&rbacv1.RoleBinding{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				createdRoleBinding = args.Get(1).(*rbacv1.RoleBinding)
+			}).
+			Return(nil)
+		c.On("List", mock.Anything, mock.IsType(&rbacv1.RoleBindingList{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				list := args.Get(1).(*rbacv1.RoleBindingList)
+				list.Items = []rbacv1.RoleBinding{*createdRoleBinding}
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		stop, err := r.ensureSCCRoleBindings(ctx, addon)
+		require.NoError(t, err)
+		assert.False(t, stop)
+
+		c.AssertExpectations(t)
+		c.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+		if assert.NotNil(t, review) {
+			assert.Equal(t, &authorizationv1.ResourceAttributes{
+				Namespace: "addon-1",
+				Verb:      "use",
+				Group:     "security.openshift.io",
+				Resource:  "securitycontextconstraints",
+				Name:      "anyuid",
+			}, review.Spec.ResourceAttributes)
+		}
+		if assert.NotNil(t, createdRoleBinding) {
+			assert.Equal(t, "system:openshift:scc:anyuid", createdRoleBinding.RoleRef.Name)
+			assert.Equal(t, []rbacv1.Subject{
+				{
+					APIGroup: rbacv1.GroupName,
+					Kind:     rbacv1.GroupKind,
+					Name:     "system:serviceaccounts:addon-1",
+				},
+			}, createdRoleBinding.Subjects)
+			assert.Equal(t, addon.Name, createdRoleBinding.Labels[commonInstanceLabel])
+		}
+	})
+
+	t.Run("removes RoleBindings of SCCs no longer granted", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithSCC()
+		addon.Spec.SCC = nil
+
+		c.On("List", mock.Anything, mock.IsType(&rbacv1.RoleBindingList{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				list := args.Get(1).(*rbacv1.RoleBindingList)
+				list.Items = []rbacv1.RoleBinding{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "addon-1-scc-anyuid",
+							Namespace: "addon-1",
+						},
+					},
+				}
+			}).
+			Return(nil)
+		c.On("Delete", mock.Anything, mock.IsType(&rbacv1.RoleBinding{}), mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		stop, err := r.ensureSCCRoleBindings(ctx, addon)
+		require.NoError(t, err)
+		assert.False(t, stop)
+
+		c.AssertExpectations(t)
+		c.AssertCalled(t, "Delete", mock.Anything,
+			mock.MatchedBy(func(rb *rbacv1.RoleBinding) bool {
+				return rb.Name == "addon-1-scc-anyuid" && rb.Namespace == "addon-1"
+			}), mock.Anything)
+		c.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects escalation", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithSCC()
+		addon.Spec.SCC.Names = []string{"anyuid", "privileged"}
+
+		c.On("Create", mock.Anything, mock.IsType(&authorizationv1.SelfSubjectAccessReview{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				review := args.Get(1).(*authorizationv1.SelfSubjectAccessReview)
+				review.Status.Allowed = review.Spec.ResourceAttributes.Name != "privileged"
+			}).
+			Return(nil)
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		stop, err := r.ensureSCCRoleBindings(ctx, addon)
+		require.NoError(t, err)
+		assert.True(t, stop)
+
+		// nothing is granted, not even the allowed SCC
+		c.AssertNotCalled(t, "Create", mock.Anything, mock.IsType(&rbacv1.RoleBinding{}), mock.Anything)
+		c.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+		c.StatusMock.AssertCalled(t, "Update", mock.Anything, addon, mock.Anything)
+		availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+		if assert.NotNil(t, availableCond) {
+			assert.Equal(t, metav1.ConditionFalse, availableCond.Status)
+			assert.Equal(t, "ConfigurationError", availableCond.Reason)
+			assert.Contains(t, availableCond.Message, `"privileged"`)
+		}
+	})
+}
+
+func TestReconcileRoleBinding(t *testing.T) {
+	roleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "addon-1-scc-anyuid",
+			Namespace: "addon-1",
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     "system:openshift:scc:anyuid",
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				APIGroup: rbacv1.GroupName,
+				Kind:     rbacv1.GroupKind,
+				Name:     "system:serviceaccounts:addon-1",
+			},
+		},
+	}
+
+	t.Run("updates subjects", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		c.On("Get", mock.Anything, client.ObjectKeyFromObject(roleBinding),
+			mock.IsType(&rbacv1.RoleBinding{})).
+			Run(func(args mock.Arguments) {
+				rb := args.Get(2).(*rbacv1.RoleBinding)
+				roleBinding.DeepCopyInto(rb)
+				rb.ResourceVersion = "123"
+				rb.Subjects = append(rb.Subjects, rbacv1.Subject{
+					Kind: rbacv1.UserKind,
+					Name: "someone",
+				})
+			}).
+			Return(nil)
+		var updatedRoleBinding *rbacv1.RoleBinding
+		c.On("Update", mock.Anything, mock.IsType(&rbacv1.RoleBinding{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				updatedRoleBinding = args.Get(1).(*rbacv1.RoleBinding)
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.reconcileRoleBinding(ctx, roleBinding.DeepCopy())
+		require.NoError(t, err)
+
+		if assert.NotNil(t, updatedRoleBinding) {
+			assert.Equal(t, roleBinding.Subjects, updatedRoleBinding.Subjects)
+			// update has to be done on the existing object
+			assert.Equal(t, "123", updatedRoleBinding.ResourceVersion)
+		}
+	})
+
+	t.Run("replaces on roleRef change", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		c.On("Get", mock.Anything, client.ObjectKeyFromObject(roleBinding),
+			mock.IsType(&rbacv1.RoleBinding{})).
+			Run(func(args mock.Arguments) {
+				rb := args.Get(2).(*rbacv1.RoleBinding)
+				roleBinding.DeepCopyInto(rb)
+				rb.RoleRef.Name = "system:openshift:scc:privileged"
+			}).
+			Return(nil)
+		c.On("Delete", mock.Anything, mock.IsType(&rbacv1.RoleBinding{}), mock.Anything).
+			Return(nil)
+		c.On("Create", mock.Anything, mock.IsType(&rbacv1.RoleBinding{}), mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.reconcileRoleBinding(ctx, roleBinding.DeepCopy())
+		require.NoError(t, err)
+
+		c.AssertCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+		c.AssertCalled(t, "Create", mock.Anything,
+			mock.MatchedBy(func(rb *rbacv1.RoleBinding) bool {
+				return rb.RoleRef.Name == "system:openshift:scc:anyuid"
+			}), mock.Anything)
+	})
+}
+
+func newTestAddonWithSCC() *addonsv1alpha1.Addon {
+	addon := newTestAddonWithCatalogSourceImage()
+	addon.Spec.SCC = &addonsv1alpha1.AddonSCC{
+		Names: []string{"anyuid"},
+	}
+	return addon
+}
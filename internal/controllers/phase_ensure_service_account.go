@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+// Ensures the ServiceAccount specified in the given Addon resource
+// and the cleanup of ServiceAccounts that are not needed anymore.
+func (r *AddonReconciler) ensureServiceAccount(
+	ctx context.Context, addon *addonsv1alpha1.Addon) error {
+	targetNamespace := getCommonInstallOptions(addon).Namespace
+
+	var wantedServiceAccountName string
+	if addon.Spec.ServiceAccount != nil {
+		desiredServiceAccount := &corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      addon.Spec.ServiceAccount.Name,
+				Namespace: targetNamespace,
+				Labels:    map[string]string{},
+			},
+		}
+		for _, secretName := range addon.Spec.ServiceAccount.ImagePullSecrets {
+			desiredServiceAccount.ImagePullSecrets = append(
+				desiredServiceAccount.ImagePullSecrets,
+				corev1.LocalObjectReference{Name: secretName})
+		}
+
+		addCommonLabels(desiredServiceAccount.Labels, addon)
+		if err := controllerutil.SetControllerReference(addon, desiredServiceAccount, r.Scheme); err != nil {
+			return fmt.Errorf("setting controller reference: %w", err)
+		}
+
+		if err := r.reconcileServiceAccount(ctx, desiredServiceAccount); err != nil {
+			return fmt.Errorf("reconciling ServiceAccount: %w", err)
+		}
+		wantedServiceAccountName = desiredServiceAccount.Name
+	}
+
+	// Ensure cleanup of ServiceAccounts that were previously created for this Addon
+	serviceAccounts := &corev1.ServiceAccountList{}
+	if err := r.List(ctx, serviceAccounts,
+		client.InNamespace(targetNamespace),
+		client.MatchingLabelsSelector{
+			Selector: commonLabelsAsLabelSelector(addon),
+		}); err != nil {
+		return fmt.Errorf("could not list owned ServiceAccounts: %w", err)
+	}
+	for i := range serviceAccounts.Items {
+		serviceAccount := &serviceAccounts.Items[i]
+		if serviceAccount.Name == wantedServiceAccountName {
+			continue
+		}
+
+		err := r.Delete(ctx, serviceAccount)
+		// don't propagate error if the ServiceAccount is already gone
+		if err != nil && !k8sApiErrors.IsNotFound(err) {
+			return fmt.Errorf("could not delete ServiceAccount: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Reconciles the ImagePullSecrets of the given ServiceAccount
+// by creating or updating the ServiceAccount if needed.
+func (r *AddonReconciler) reconcileServiceAccount(
+	ctx context.Context, serviceAccount *corev1.ServiceAccount) error {
+	currentServiceAccount := &corev1.ServiceAccount{}
+
+	err := r.Get(ctx, client.ObjectKeyFromObject(serviceAccount), currentServiceAccount)
+	if k8sApiErrors.IsNotFound(err) {
+		return r.Create(ctx, serviceAccount)
+	}
+	if err != nil {
+		return fmt.Errorf("getting ServiceAccount: %w", err)
+	}
+
+	// only update when the pull secrets have changed
+	if !equality.Semantic.DeepEqual(
+		currentServiceAccount.ImagePullSecrets, serviceAccount.ImagePullSecrets) {
+		currentServiceAccount.ImagePullSecrets = serviceAccount.ImagePullSecrets
+		return r.Update(ctx, currentServiceAccount)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestEnsureServiceAccount(t *testing.T) {
+	t.Run("creates ServiceAccount with pull secrets", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithServiceAccount()
+
+		c.On("Get", mock.Anything, client.ObjectKey{
+			Name:      "addon-sa",
+			Namespace: "addon-1",
+		}, mock.IsType(&corev1.ServiceAccount{})).
+			Return(newTestErrNotFound())
+		var createdServiceAccount *corev1.ServiceAccount
+		c.On("Create", mock.Anything, mock.IsType(&corev1.ServiceAccount{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				createdServiceAccount = args.Get(1).(*corev1.ServiceAccount)
+			}).
+			Return(nil)
+		c.On("List", mock.Anything, mock.IsType(&corev1.ServiceAccountList{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				list := args.Get(1).(*corev1.ServiceAccountList)
+				list.Items = []corev1.ServiceAccount{*createdServiceAccount}
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.ensureServiceAccount(ctx, addon)
+		require.NoError(t, err)
+
+		c.AssertExpectations(t)
+		c.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+		if assert.NotNil(t, createdServiceAccount) {
+			assert.Equal(t, []corev1.LocalObjectReference{
+				{Name: "pull-secret"},
+			}, createdServiceAccount.ImagePullSecrets)
+			assert.Equal(t, addon.Name, createdServiceAccount.Labels[commonInstanceLabel])
+			if assert.Len(t, createdServiceAccount.OwnerReferences, 1) {
+				assert.Equal(t, addon.Name, createdServiceAccount.OwnerReferences[0].Name)
+			}
+		}
+	})
+
+	t.Run("removes unwanted ServiceAccount", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithServiceAccount()
+		addon.Spec.ServiceAccount = nil
+
+		c.On("List", mock.Anything, mock.IsType(&corev1.ServiceAccountList{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				list := args.Get(1).(*corev1.ServiceAccountList)
+				list.Items = []corev1.ServiceAccount{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "addon-sa",
+							Namespace: "addon-1",
+						},
+					},
+				}
+			}).
+			Return(nil)
+		c.On("Delete", mock.Anything, mock.IsType(&corev1.ServiceAccount{}), mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.ensureServiceAccount(ctx, addon)
+		require.NoError(t, err)
+
+		c.AssertExpectations(t)
+		c.AssertCalled(t, "Delete", mock.Anything,
+			mock.MatchedBy(func(sa *corev1.ServiceAccount) bool {
+				return sa.Name == "addon-sa" && sa.Namespace == "addon-1"
+			}), mock.Anything)
+		c.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestReconcileServiceAccount(t *testing.T) {
+	serviceAccount := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "addon-sa",
+			Namespace: "addon-1",
+		},
+		ImagePullSecrets: []corev1.LocalObjectReference{
+			{Name: "pull-secret"},
+		},
+	}
+
+	t.Run("no-op", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		c.On("Get", mock.Anything, client.ObjectKeyFromObject(serviceAccount),
+			mock.IsType(&corev1.ServiceAccount{})).
+			Run(func(args mock.Arguments) {
+				sa := args.Get(2).(*corev1.ServiceAccount)
+				serviceAccount.DeepCopyInto(sa)
+				// token secrets are managed by kubernetes
+				sa.Secrets = []corev1.ObjectReference{{Name: "addon-sa-token-abcde"}}
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.reconcileServiceAccount(ctx, serviceAccount.DeepCopy())
+		require.NoError(t, err)
+		c.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("corrects drift", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		c.On("Get", mock.Anything, client.ObjectKeyFromObject(serviceAccount),
+			mock.IsType(&corev1.ServiceAccount{})).
+			Run(func(args mock.Arguments) {
+				sa := args.Get(2).(*corev1.ServiceAccount)
+				serviceAccount.DeepCopyInto(sa)
+				sa.ResourceVersion = "123"
+				sa.ImagePullSecrets = []corev1.LocalObjectReference{
+					{Name: "something-else"},
+				}
+			}).
+			Return(nil)
+		var updatedServiceAccount *corev1.ServiceAccount
+		c.On("Update", mock.Anything, mock.IsType(&corev1.ServiceAccount{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				updatedServiceAccount = args.Get(1).(*corev1.ServiceAccount)
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.reconcileServiceAccount(ctx, serviceAccount.DeepCopy())
+		require.NoError(t, err)
+
+		if assert.NotNil(t, updatedServiceAccount) {
+			assert.Equal(t, serviceAccount.ImagePullSecrets, updatedServiceAccount.ImagePullSecrets)
+			// update has to be done on the existing object
+			assert.Equal(t, "123", updatedServiceAccount.ResourceVersion)
+		}
+	})
+}
+
+func newTestAddonWithServiceAccount() *addonsv1alpha1.Addon {
+	addon := newTestAddonWithCatalogSourceImage()
+	addon.Spec.ServiceAccount = &addonsv1alpha1.AddonServiceAccount{
+		Name:             "addon-sa",
+		ImagePullSecrets: []string{"pull-secret"},
+	}
+	return addon
+}
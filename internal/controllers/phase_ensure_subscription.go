@@ -21,24 +21,17 @@ func (r *AddonReconciler) ensureSubscription(
 	addon *addonsv1alpha1.Addon,
 	catalogSource *operatorsv1alpha1.CatalogSource,
 ) (
-	currentCSVKey client.ObjectKey,
+	installedCSVKey, currentCSVKey client.ObjectKey,
 	requeue bool,
 	err error,
 ) {
-	var commonInstallOptions addonsv1alpha1.AddonInstallOLMCommon
-	switch addon.Spec.Install.Type {
-	case addonsv1alpha1.OLMAllNamespaces:
-		commonInstallOptions = addon.Spec.Install.
-			OLMAllNamespaces.AddonInstallOLMCommon
-	case addonsv1alpha1.OLMOwnNamespace:
-		commonInstallOptions = addon.Spec.Install.
-			OLMOwnNamespace.AddonInstallOLMCommon
-	}
+	commonInstallOptions := getCommonInstallOptions(addon)
 
 	desiredSubscription := &operatorsv1alpha1.Subscription{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      addon.Name,
 			Namespace: commonInstallOptions.Namespace,
+			Labels:    map[string]string{},
 		},
 		Spec: &operatorsv1alpha1.SubscriptionSpec{
 			CatalogSource:          catalogSource.Name,
@@ -58,22 +51,26 @@ func (r *AddonReconciler) ensureSubscription(
 	}
 	addCommonLabels(desiredSubscription.Labels, addon)
 	if err := controllerutil.SetControllerReference(addon, desiredSubscription, r.Scheme); err != nil {
-		return client.ObjectKey{}, false, fmt.Errorf("setting controller reference: %w", err)
+		return client.ObjectKey{}, client.ObjectKey{}, false, fmt.Errorf("setting controller reference: %w", err)
 	}
 
-	observedSubscription, err := r.reconcileSubscription(
-		ctx, desiredSubscription)
+	var observedSubscription *operatorsv1alpha1.Subscription
+	err = retryOnOLMObjectConflict(ctx, func() (err error) {
+		observedSubscription, err = r.reconcileSubscription(
+			ctx, desiredSubscription.DeepCopy())
+		return err
+	})
 	if err != nil {
-		return client.ObjectKey{}, false, fmt.Errorf("reconciling Subscription: %w", err)
+		return client.ObjectKey{}, client.ObjectKey{}, false, fmt.Errorf("reconciling Subscription: %w", err)
 	}
 
 	if len(observedSubscription.Status.InstalledCSV) == 0 ||
 		len(observedSubscription.Status.CurrentCSV) == 0 {
 		log.Info("requeue", "reason", "csv not linked in subscription")
-		return client.ObjectKey{}, true, nil
+		return client.ObjectKey{}, client.ObjectKey{}, true, nil
 	}
 
-	installedCSVKey := client.ObjectKey{
+	installedCSVKey = client.ObjectKey{
 		Name:      observedSubscription.Status.InstalledCSV,
 		Namespace: commonInstallOptions.Namespace,
 	}
@@ -87,10 +84,10 @@ func (r *AddonReconciler) ensureSubscription(
 		// Mapping changes need to requeue, because we could have lost events before or during
 		// setting up the mapping, see csvEventHandler implementation for a longer description.
 		log.Info("requeue", "reason", "csv-addon mapping changed")
-		return client.ObjectKey{}, true, nil
+		return client.ObjectKey{}, client.ObjectKey{}, true, nil
 	}
 
-	return currentCSVKey, false, nil
+	return installedCSVKey, currentCSVKey, false, nil
 }
 
 func (r *AddonReconciler) reconcileSubscription(
@@ -112,8 +109,9 @@ func (r *AddonReconciler) reconcileSubscription(
 	// keep installPlanApproval value of existing object
 	subscription.Spec.InstallPlanApproval = currentSubscription.Spec.InstallPlanApproval
 
-	// only update when spec has changed
-	if !equality.Semantic.DeepEqual(
+	labelsChanged := reconcileCommonLabels(currentSubscription, subscription)
+	// only update when spec or labels have changed
+	if labelsChanged || !equality.Semantic.DeepEqual(
 		subscription.Spec, currentSubscription.Spec) {
 		// copy new spec into existing object and update in the k8s api
 		currentSubscription.Spec = subscription.Spec
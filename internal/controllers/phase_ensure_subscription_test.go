@@ -0,0 +1,168 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestEnsureSubscription_AlreadyExistsOnCreate(t *testing.T) {
+	addon := newTestAddonWithCatalogSourceImage()
+	catalogSource := &operatorsv1alpha1.CatalogSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      addon.Name,
+			Namespace: "addon-1",
+		},
+	}
+	subscriptionKey := client.ObjectKey{
+		Name:      addon.Name,
+		Namespace: "addon-1",
+	}
+
+	c := testutil.NewClient()
+	// cache has not observed the Subscription yet
+	c.On("Get", mock.Anything, subscriptionKey,
+		mock.IsType(&operatorsv1alpha1.Subscription{})).
+		Return(newTestErrNotFound()).Once()
+	c.On("Create", mock.Anything,
+		mock.IsType(&operatorsv1alpha1.Subscription{}), mock.Anything).
+		Return(k8sApiErrors.NewAlreadyExists(
+			operatorsv1alpha1.Resource("subscriptions"), addon.Name))
+	c.On("Get", mock.Anything, subscriptionKey,
+		mock.IsType(&operatorsv1alpha1.Subscription{})).
+		Run(func(args mock.Arguments) {
+			sub := args.Get(2).(*operatorsv1alpha1.Subscription)
+			sub.ResourceVersion = "123"
+			sub.Spec = &operatorsv1alpha1.SubscriptionSpec{
+				CatalogSource:          "other",
+				CatalogSourceNamespace: "addon-1",
+				InstallPlanApproval:    operatorsv1alpha1.ApprovalManual,
+			}
+		}).
+		Return(nil)
+	var updatedSubscription *operatorsv1alpha1.Subscription
+	c.On("Update", mock.Anything,
+		mock.IsType(&operatorsv1alpha1.Subscription{}), mock.Anything).
+		Run(func(args mock.Arguments) {
+			updatedSubscription = args.Get(1).(*operatorsv1alpha1.Subscription)
+		}).
+		Return(nil)
+
+	r := &AddonReconciler{
+		Client: c,
+		Log:    testutil.NewLogger(t),
+		Scheme: newTestSchemeWithAddonsv1alpha1(),
+	}
+
+	ctx := context.Background()
+	_, _, requeue, err := r.ensureSubscription(ctx, testutil.NewLogger(t), addon, catalogSource)
+	require.NoError(t, err)
+	// CSV is not yet linked in the Subscription status
+	assert.True(t, requeue)
+
+	c.AssertNumberOfCalls(t, "Create", 1)
+	if assert.NotNil(t, updatedSubscription) {
+		assert.Equal(t, catalogSource.Name, updatedSubscription.Spec.CatalogSource)
+		assert.Equal(t, operatorsv1alpha1.ApprovalManual,
+			updatedSubscription.Spec.InstallPlanApproval)
+		// update has to be done on the existing object
+		assert.Equal(t, "123", updatedSubscription.ResourceVersion)
+	}
+}
+
+func TestEnsureSubscription_ConflictOnUpdate(t *testing.T) {
+	addon := newTestAddonWithCatalogSourceImage()
+	catalogSource := &operatorsv1alpha1.CatalogSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      addon.Name,
+			Namespace: "addon-1",
+		},
+	}
+
+	c := testutil.NewClient()
+	c.On("Get", mock.Anything, testutil.IsObjectKey,
+		mock.IsType(&operatorsv1alpha1.Subscription{})).
+		Run(func(args mock.Arguments) {
+			sub := args.Get(2).(*operatorsv1alpha1.Subscription)
+			sub.Spec = &operatorsv1alpha1.SubscriptionSpec{
+				CatalogSource: "other",
+			}
+		}).
+		Return(nil)
+	c.On("Update", mock.Anything,
+		mock.IsType(&operatorsv1alpha1.Subscription{}), mock.Anything).
+		Return(k8sApiErrors.NewConflict(
+			operatorsv1alpha1.Resource("subscriptions"), addon.Name, nil)).Once()
+	c.On("Update", mock.Anything,
+		mock.IsType(&operatorsv1alpha1.Subscription{}), mock.Anything).
+		Return(nil)
+
+	r := &AddonReconciler{
+		Client: c,
+		Log:    testutil.NewLogger(t),
+		Scheme: newTestSchemeWithAddonsv1alpha1(),
+	}
+
+	ctx := context.Background()
+	_, _, _, err := r.ensureSubscription(ctx, testutil.NewLogger(t), addon, catalogSource)
+	require.NoError(t, err)
+
+	// object is re-fetched before updating again
+	c.AssertNumberOfCalls(t, "Get", 2)
+	c.AssertNumberOfCalls(t, "Update", 2)
+}
+
+func TestEnsureSubscription_StampsCommonLabels(t *testing.T) {
+	addon := newTestAddonWithCatalogSourceImage()
+	catalogSource := &operatorsv1alpha1.CatalogSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      addon.Name,
+			Namespace: "addon-1",
+		},
+	}
+
+	c := testutil.NewClient()
+	// Subscription created before it carried common labels
+	c.On("Get", mock.Anything, testutil.IsObjectKey,
+		mock.IsType(&operatorsv1alpha1.Subscription{})).
+		Run(func(args mock.Arguments) {
+			sub := args.Get(2).(*operatorsv1alpha1.Subscription)
+			sub.Spec = &operatorsv1alpha1.SubscriptionSpec{
+				CatalogSource:          catalogSource.Name,
+				CatalogSourceNamespace: catalogSource.Namespace,
+			}
+		}).
+		Return(nil)
+	var updatedSubscription *operatorsv1alpha1.Subscription
+	c.On("Update", mock.Anything,
+		mock.IsType(&operatorsv1alpha1.Subscription{}), mock.Anything).
+		Run(func(args mock.Arguments) {
+			updatedSubscription = args.Get(1).(*operatorsv1alpha1.Subscription)
+		}).
+		Return(nil)
+
+	r := &AddonReconciler{
+		Client: c,
+		Log:    testutil.NewLogger(t),
+		Scheme: newTestSchemeWithAddonsv1alpha1(),
+	}
+
+	ctx := context.Background()
+	_, _, _, err := r.ensureSubscription(ctx, testutil.NewLogger(t), addon, catalogSource)
+	require.NoError(t, err)
+
+	if assert.NotNil(t, updatedSubscription) {
+		assert.True(t, commonLabelsAsLabelSelector(addon).Matches(
+			labels.Set(updatedSubscription.Labels)))
+	}
+}
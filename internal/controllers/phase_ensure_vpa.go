@@ -0,0 +1,188 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+// The VerticalPodAutoscaler API is not vendored and its CRD is optional,
+// so VerticalPodAutoscalers are handled as unstructured objects.
+var vpaGVK = schema.GroupVersionKind{
+	Group:   "autoscaling.k8s.io",
+	Version: "v1",
+	Kind:    "VerticalPodAutoscaler",
+}
+
+type ensureVPAResult int
+
+const (
+	ensureVPAResultNil   ensureVPAResult = iota
+	ensureVPAResultStop  ensureVPAResult = iota
+	ensureVPAResultRetry ensureVPAResult = iota
+)
+
+// Ensures the VerticalPodAutoscaler specified in the given Addon resource
+// and the cleanup of VerticalPodAutoscalers that are not needed anymore.
+// returns an ensureVPAResult that signals the caller if they have to
+// stop or retry reconciliation of the surrounding Addon resource
+func (r *AddonReconciler) ensureVPA(
+	ctx context.Context, addon *addonsv1alpha1.Addon,
+) (ensureVPAResult, error) {
+	targetNamespace := getCommonInstallOptions(addon).Namespace
+
+	var wantedVPAName string
+	if addon.Spec.VPA != nil {
+		if err := validateVPA(addon.Spec.VPA); err != nil {
+			// invalid configuration
+			return ensureVPAResultStop, r.reportConfigurationError(ctx, addon,
+				fmt.Sprintf(".spec.vpa is invalid: %s", err))
+		}
+
+		desiredVPA := newVPA(addon.Name, targetNamespace, addon.Spec.VPA)
+		labels := map[string]string{}
+		addCommonLabels(labels, addon)
+		desiredVPA.SetLabels(labels)
+		if err := controllerutil.SetControllerReference(addon, desiredVPA, r.Scheme); err != nil {
+			return ensureVPAResultNil, fmt.Errorf("setting controller reference: %w", err)
+		}
+
+		err := r.reconcileVPA(ctx, desiredVPA)
+		if meta.IsNoMatchError(err) {
+			// CRD is not installed, retry in case the VPA operator gets installed later
+			return ensureVPAResultRetry, r.reportVPANotInstalled(ctx, addon)
+		}
+		if err != nil {
+			return ensureVPAResultNil, fmt.Errorf("reconciling VerticalPodAutoscaler: %w", err)
+		}
+		wantedVPAName = desiredVPA.GetName()
+	}
+
+	// Ensure cleanup of VerticalPodAutoscalers that were previously created for this Addon
+	vpas := &unstructured.UnstructuredList{}
+	vpas.SetGroupVersionKind(vpaGVK.GroupVersion().WithKind(vpaGVK.Kind + "List"))
+	err := r.List(ctx, vpas,
+		client.InNamespace(targetNamespace),
+		client.MatchingLabelsSelector{
+			Selector: commonLabelsAsLabelSelector(addon),
+		})
+	if meta.IsNoMatchError(err) {
+		// without the CRD, there is nothing to clean up
+		return ensureVPAResultNil, nil
+	}
+	if err != nil {
+		return ensureVPAResultNil, fmt.Errorf("could not list owned VerticalPodAutoscalers: %w", err)
+	}
+	for i := range vpas.Items {
+		vpa := &vpas.Items[i]
+		if vpa.GetName() == wantedVPAName {
+			continue
+		}
+
+		err := r.Delete(ctx, vpa)
+		// don't propagate error if the VerticalPodAutoscaler is already gone
+		if err != nil && !k8sApiErrors.IsNotFound(err) {
+			return ensureVPAResultNil, fmt.Errorf("could not delete VerticalPodAutoscaler: %w", err)
+		}
+	}
+
+	return ensureVPAResultNil, nil
+}
+
+// Report Addon status to communicate that the VerticalPodAutoscaler CRD is not installed
+func (r *AddonReconciler) reportVPANotInstalled(
+	ctx context.Context, addon *addonsv1alpha1.Addon) error {
+	meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+		Type:               addonsv1alpha1.Available,
+		Status:             metav1.ConditionFalse,
+		Reason:             "VPANotInstalled",
+		Message:            "VerticalPodAutoscaler CRD is not installed on the cluster",
+		ObservedGeneration: addon.Generation,
+	})
+	addon.Status.ObservedGeneration = addon.Generation
+	addon.Status.Phase = addonsv1alpha1.PhasePending
+	return r.updateAddonStatus(ctx, addon)
+}
+
+// Builds a VerticalPodAutoscaler sizing the Deployment referenced by the given AddonVPA.
+func newVPA(name, namespace string, vpa *addonsv1alpha1.AddonVPA) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"targetRef": map[string]interface{}{
+					"apiVersion": appsv1.SchemeGroupVersion.String(),
+					"kind":       "Deployment",
+					"name":       vpa.DeploymentName,
+				},
+				"updatePolicy": map[string]interface{}{
+					"updateMode": string(vpa.UpdateMode),
+				},
+			},
+		},
+	}
+	obj.SetGroupVersionKind(vpaGVK)
+	obj.SetName(name)
+	obj.SetNamespace(namespace)
+	return obj
+}
+
+// Reconciles the target and update policy of the given VerticalPodAutoscaler
+// by creating or updating the VerticalPodAutoscaler if needed.
+func (r *AddonReconciler) reconcileVPA(
+	ctx context.Context, vpa *unstructured.Unstructured) error {
+	currentVPA := &unstructured.Unstructured{}
+	currentVPA.SetGroupVersionKind(vpaGVK)
+
+	err := r.Get(ctx, client.ObjectKeyFromObject(vpa), currentVPA)
+	if k8sApiErrors.IsNotFound(err) {
+		return r.Create(ctx, vpa)
+	}
+	if meta.IsNoMatchError(err) {
+		// not wrapped, so the caller can detect the missing CRD
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("getting VerticalPodAutoscaler: %w", err)
+	}
+
+	var changed bool
+	for _, field := range []string{"targetRef", "updatePolicy"} {
+		wanted, _, _ := unstructured.NestedFieldNoCopy(vpa.Object, "spec", field)
+		current, _, _ := unstructured.NestedFieldNoCopy(currentVPA.Object, "spec", field)
+		if equality.Semantic.DeepEqual(current, wanted) {
+			continue
+		}
+
+		if err := unstructured.SetNestedField(
+			currentVPA.Object, wanted, "spec", field); err != nil {
+			return fmt.Errorf("setting .spec.%s: %w", field, err)
+		}
+		changed = true
+	}
+	if changed {
+		return r.Update(ctx, currentVPA)
+	}
+	return nil
+}
+
+// Validates the target reference of the given AddonVPA.
+func validateVPA(vpa *addonsv1alpha1.AddonVPA) error {
+	if errs := validation.IsDNS1123Subdomain(vpa.DeploymentName); len(errs) > 0 {
+		return fmt.Errorf("invalid deploymentName %q: %s",
+			vpa.DeploymentName, strings.Join(errs, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestEnsureVPA(t *testing.T) {
+	t.Run("creates VPA", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithVPA()
+
+		c.On("Get", mock.Anything, client.ObjectKey{
+			Name:      addon.Name,
+			Namespace: "addon-1",
+		}, mock.IsType(&unstructured.Unstructured{})).
+			Return(newTestErrNotFound())
+		var createdVPA *unstructured.Unstructured
+		c.On("Create", mock.Anything, mock.IsType(&unstructured.Unstructured{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				createdVPA = args.Get(1).(*unstructured.Unstructured)
+			}).
+			Return(nil)
+		c.On("List", mock.Anything, mock.IsType(&unstructured.UnstructuredList{}), mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		result, err := r.ensureVPA(ctx, addon)
+		require.NoError(t, err)
+		assert.Equal(t, ensureVPAResultNil, result)
+
+		c.AssertExpectations(t)
+		if assert.NotNil(t, createdVPA) {
+			assert.Equal(t, vpaGVK, createdVPA.GroupVersionKind())
+			targetName, _, _ := unstructured.NestedString(createdVPA.Object, "spec", "targetRef", "name")
+			assert.Equal(t, "manager", targetName)
+			updateMode, _, _ := unstructured.NestedString(createdVPA.Object, "spec", "updatePolicy", "updateMode")
+			assert.Equal(t, "Off", updateMode)
+			assert.Equal(t, addon.Name, createdVPA.GetLabels()[commonInstanceLabel])
+		}
+	})
+
+	t.Run("deletes VPA removed from spec", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithVPA()
+		addon.Spec.VPA = nil
+
+		c.On("List", mock.Anything, mock.IsType(&unstructured.UnstructuredList{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				list := args.Get(1).(*unstructured.UnstructuredList)
+				list.Items = []unstructured.Unstructured{
+					*newVPA(addon.Name, "addon-1", newTestAddonWithVPA().Spec.VPA),
+				}
+			}).
+			Return(nil)
+		c.On("Delete", mock.Anything, mock.IsType(&unstructured.Unstructured{}), mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		result, err := r.ensureVPA(ctx, addon)
+		require.NoError(t, err)
+		assert.Equal(t, ensureVPAResultNil, result)
+
+		c.AssertExpectations(t)
+		c.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing CRD", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithVPA()
+
+		c.On("Get", mock.Anything, mock.Anything, mock.IsType(&unstructured.Unstructured{})).
+			Return(&meta.NoKindMatchError{
+				GroupKind: vpaGVK.GroupKind(),
+			})
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		result, err := r.ensureVPA(ctx, addon)
+		require.NoError(t, err)
+		assert.Equal(t, ensureVPAResultRetry, result)
+
+		c.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+		availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+		if assert.NotNil(t, availableCond) {
+			assert.Equal(t, metav1.ConditionFalse, availableCond.Status)
+			assert.Equal(t, "VPANotInstalled", availableCond.Reason)
+		}
+	})
+
+	t.Run("tolerates missing CRD without VPA", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithVPA()
+		addon.Spec.VPA = nil
+
+		c.On("List", mock.Anything, mock.IsType(&unstructured.UnstructuredList{}), mock.Anything).
+			Return(&meta.NoKindMatchError{
+				GroupKind: vpaGVK.GroupKind(),
+			})
+
+		ctx := context.Background()
+		result, err := r.ensureVPA(ctx, addon)
+		require.NoError(t, err)
+		assert.Equal(t, ensureVPAResultNil, result)
+	})
+}
+
+func TestReconcileVPA(t *testing.T) {
+	vpa := newVPA("addon-1", "addon-1", newTestAddonWithVPA().Spec.VPA)
+
+	t.Run("updates mode", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		c.On("Get", mock.Anything, client.ObjectKeyFromObject(vpa),
+			mock.IsType(&unstructured.Unstructured{})).
+			Run(func(args mock.Arguments) {
+				currentVPA := args.Get(2).(*unstructured.Unstructured)
+				vpa.DeepCopyInto(currentVPA)
+				currentVPA.SetResourceVersion("123")
+			}).
+			Return(nil)
+		var updatedVPA *unstructured.Unstructured
+		c.On("Update", mock.Anything, mock.IsType(&unstructured.Unstructured{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				updatedVPA = args.Get(1).(*unstructured.Unstructured)
+			}).
+			Return(nil)
+
+		desiredVPA := newVPA("addon-1", "addon-1", &addonsv1alpha1.AddonVPA{
+			DeploymentName: "manager",
+			UpdateMode:     addonsv1alpha1.VPAUpdateModeAuto,
+		})
+
+		ctx := context.Background()
+		err := r.reconcileVPA(ctx, desiredVPA)
+		require.NoError(t, err)
+
+		if assert.NotNil(t, updatedVPA) {
+			updateMode, _, _ := unstructured.NestedString(updatedVPA.Object, "spec", "updatePolicy", "updateMode")
+			assert.Equal(t, "Auto", updateMode)
+			// update has to be done on the existing object
+			assert.Equal(t, "123", updatedVPA.GetResourceVersion())
+		}
+	})
+
+	t.Run("no-op when spec matches", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		c.On("Get", mock.Anything, client.ObjectKeyFromObject(vpa),
+			mock.IsType(&unstructured.Unstructured{})).
+			Run(func(args mock.Arguments) {
+				vpa.DeepCopyInto(args.Get(2).(*unstructured.Unstructured))
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.reconcileVPA(ctx, vpa.DeepCopy())
+		require.NoError(t, err)
+		c.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func newTestAddonWithVPA() *addonsv1alpha1.Addon {
+	addon := newTestAddonWithCatalogSourceImage()
+	addon.Spec.VPA = &addonsv1alpha1.AddonVPA{
+		DeploymentName: "manager",
+		UpdateMode:     addonsv1alpha1.VPAUpdateModeOff,
+	}
+	return addon
+}
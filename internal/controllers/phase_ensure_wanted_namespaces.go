@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -22,9 +23,32 @@ func (r *AddonReconciler) ensureWantedNamespaces(
 	ctx context.Context, addon *addonsv1alpha1.Addon) (stopAndRetry bool, err error) {
 	var unreadyNamespaces []string
 	var collidedNamespaces []string
+	var missingNamespaces []string
+	var recreatedNamespaces []string
+	var observedNamespaces []string
+
+	// Any observed Namespace we can't find now was deleted out from under us.
+	wasObserved := map[string]struct{}{}
+	for _, name := range addon.Status.ObservedNamespaces {
+		wasObserved[name] = struct{}{}
+	}
 
 	for _, namespace := range addon.Spec.Namespaces {
-		ensuredNamespace, err := r.ensureNamespace(ctx, addon, namespace.Name)
+		_, observed := wasObserved[namespace.Name]
+		if observed && namespace.RecreationPolicy == addonsv1alpha1.NamespaceRecreationPolicyNever {
+			exists, err := r.namespaceExists(ctx, namespace.Name)
+			if err != nil {
+				return false, err
+			}
+			if !exists {
+				missingNamespaces = append(missingNamespaces, namespace.Name)
+				// keep reporting the Namespace as missing until it is removed from the spec
+				observedNamespaces = append(observedNamespaces, namespace.Name)
+				continue
+			}
+		}
+
+		ensuredNamespace, created, err := r.ensureNamespace(ctx, addon, namespace.Name)
 		if err != nil {
 			if errors.Is(err, errNotOwnedByUs) {
 				collidedNamespaces = append(collidedNamespaces, namespace.Name)
@@ -33,12 +57,25 @@ func (r *AddonReconciler) ensureWantedNamespaces(
 
 			return false, err
 		}
+		observedNamespaces = append(observedNamespaces, ensuredNamespace.Name)
+
+		if created && observed {
+			recreatedNamespaces = append(recreatedNamespaces, ensuredNamespace.Name)
+		}
 
 		if ensuredNamespace.Status.Phase != corev1.NamespaceActive {
 			unreadyNamespaces = append(unreadyNamespaces, ensuredNamespace.Name)
 		}
 	}
 
+	// Remember which Namespaces exist, so their deletion can be detected,
+	// even if the Addon never became Available.
+	statusChanged := !equality.Semantic.DeepEqual(addon.Status.ObservedNamespaces, observedNamespaces)
+	addon.Status.ObservedNamespaces = observedNamespaces
+	if setNamespaceMissingCondition(addon, missingNamespaces, recreatedNamespaces) {
+		statusChanged = true
+	}
+
 	if len(collidedNamespaces) > 0 {
 		meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
 			Type:   addonsv1alpha1.Available,
@@ -51,7 +88,7 @@ func (r *AddonReconciler) ensureWantedNamespaces(
 		})
 		addon.Status.ObservedGeneration = addon.Generation
 		addon.Status.Phase = addonsv1alpha1.PhasePending
-		err := r.Status().Update(ctx, addon)
+		err := r.updateAddonStatus(ctx, addon)
 		if err != nil {
 			return false, err
 		}
@@ -59,6 +96,27 @@ func (r *AddonReconciler) ensureWantedNamespaces(
 		return true, nil
 	}
 
+	if deletedNamespaces := append(missingNamespaces, recreatedNamespaces...); len(deletedNamespaces) > 0 {
+		meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+			Type:   addonsv1alpha1.Available,
+			Status: metav1.ConditionFalse,
+			Reason: "NamespaceMissing",
+			Message: fmt.Sprintf(
+				"Namespaces were deleted externally: %s",
+				strings.Join(deletedNamespaces, ", ")),
+			ObservedGeneration: addon.Generation,
+		})
+		addon.Status.ObservedGeneration = addon.Generation
+		addon.Status.Phase = addonsv1alpha1.PhasePending
+		err := r.updateAddonStatus(ctx, addon)
+		if err != nil {
+			return false, err
+		}
+		// everything within the Namespaces is gone as well,
+		// signal caller to stop and retry once the Namespaces are back
+		return true, nil
+	}
+
 	if len(unreadyNamespaces) > 0 {
 		meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
 			Type:   addonsv1alpha1.Available,
@@ -71,14 +129,79 @@ func (r *AddonReconciler) ensureWantedNamespaces(
 		})
 		addon.Status.ObservedGeneration = addon.Generation
 		addon.Status.Phase = addonsv1alpha1.PhasePending
-		return false, r.Status().Update(ctx, addon)
+		return false, r.updateAddonStatus(ctx, addon)
 	}
 
+	if statusChanged {
+		return false, r.updateAddonStatus(ctx, addon)
+	}
 	return false, nil
 }
 
+// Reflects Namespaces of the given Addon that were deleted externally
+// in the NamespaceMissing condition.
+// The condition is only added once a Namespace went missing.
+func setNamespaceMissingCondition(
+	addon *addonsv1alpha1.Addon, missingNamespaces, recreatedNamespaces []string) (changed bool) {
+	currentCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.NamespaceMissing)
+
+	var cond metav1.Condition
+	switch {
+	case len(missingNamespaces) > 0:
+		cond = metav1.Condition{
+			Type:   addonsv1alpha1.NamespaceMissing,
+			Status: metav1.ConditionTrue,
+			Reason: "NamespacesDeleted",
+			Message: fmt.Sprintf(
+				"Namespaces were deleted externally and are not recreated: %s",
+				strings.Join(missingNamespaces, ", ")),
+		}
+	case len(recreatedNamespaces) > 0:
+		cond = metav1.Condition{
+			Type:   addonsv1alpha1.NamespaceMissing,
+			Status: metav1.ConditionTrue,
+			Reason: "NamespacesRecreated",
+			Message: fmt.Sprintf(
+				"Namespaces were deleted externally and have been recreated: %s",
+				strings.Join(recreatedNamespaces, ", ")),
+		}
+	case currentCond == nil:
+		return false
+	default:
+		cond = metav1.Condition{
+			Type:    addonsv1alpha1.NamespaceMissing,
+			Status:  metav1.ConditionFalse,
+			Reason:  "NamespacesPresent",
+			Message: "All Namespaces are present.",
+		}
+	}
+	cond.ObservedGeneration = addon.Generation
+
+	if currentCond != nil &&
+		currentCond.Status == cond.Status &&
+		currentCond.Reason == cond.Reason &&
+		currentCond.Message == cond.Message {
+		return false
+	}
+	meta.SetStatusCondition(&addon.Status.Conditions, cond)
+	return true
+}
+
+// Checks whether a Namespace with the given name exists.
+func (r *AddonReconciler) namespaceExists(ctx context.Context, name string) (bool, error) {
+	err := r.Get(ctx, client.ObjectKey{Name: name}, &corev1.Namespace{})
+	if k8sApiErrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("getting Namespace: %w", err)
+	}
+	return true, nil
+}
+
 // Ensure a single Namespace for the given Addon resource
-func (r *AddonReconciler) ensureNamespace(ctx context.Context, addon *addonsv1alpha1.Addon, name string) (*corev1.Namespace, error) {
+// returns a bool that signals whether the Namespace had to be created
+func (r *AddonReconciler) ensureNamespace(ctx context.Context, addon *addonsv1alpha1.Addon, name string) (*corev1.Namespace, bool, error) {
 	namespace := &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:   name,
@@ -86,20 +209,23 @@ func (r *AddonReconciler) ensureNamespace(ctx context.Context, addon *addonsv1al
 		},
 	}
 	addCommonLabels(namespace.Labels, addon)
+	addPodSecurityLabels(namespace.Labels, addon.Spec.PodSecurity)
+	addMonitoringLabels(namespace.Labels, addon)
 
 	err := controllerutil.SetControllerReference(addon, namespace, r.Scheme)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	return reconcileNamespace(ctx, r.Client, namespace)
 }
 
-// reconciles a Namespace and returns the current object as observed.
+// reconciles a Namespace and returns the current object as observed
+// and whether it had to be created.
 // prevents adoption of Namespaces (unowned or owned by something else)
 // reconciling a Namespace means: creating it when it is not present
 // and erroring if our controller is not the owner of said Namespace
-func reconcileNamespace(ctx context.Context, c client.Client, namespace *corev1.Namespace) (*corev1.Namespace, error) {
+func reconcileNamespace(ctx context.Context, c client.Client, namespace *corev1.Namespace) (*corev1.Namespace, bool, error) {
 
 	currentNamespace := &corev1.Namespace{}
 
@@ -109,18 +235,65 @@ func reconcileNamespace(ctx context.Context, c client.Client, namespace *corev1.
 		}, currentNamespace)
 		if err != nil {
 			if k8sApiErrors.IsNotFound(err) {
-				return namespace, c.Create(ctx, namespace)
+				return namespace, true, c.Create(ctx, namespace)
 			}
-			return nil, err
+			return nil, false, err
 		}
 	}
 
 	if len(currentNamespace.OwnerReferences) == 0 ||
 		!hasEqualControllerReference(currentNamespace, namespace) {
-		return nil, errNotOwnedByUs
+		return nil, false, errNotOwnedByUs
+	}
+
+	labelsChanged := reconcileCommonLabels(currentNamespace, namespace)
+	if reconcilePodSecurityLabels(currentNamespace, namespace) || labelsChanged {
+		return currentNamespace, false, c.Update(ctx, currentNamespace)
+	}
+
+	return currentNamespace, false, nil
+}
+
+// PodSecurity admission modes configured for the Namespaces of an Addon.
+var podSecurityLabels = []string{
+	"pod-security.kubernetes.io/enforce",
+	"pod-security.kubernetes.io/warn",
+	"pod-security.kubernetes.io/audit",
+}
+
+// Adds the labels enforcing the given PodSecurity level, if any.
+func addPodSecurityLabels(labels map[string]string, level addonsv1alpha1.PodSecurityLevel) {
+	if labels == nil || len(level) == 0 {
+		return
 	}
 
-	return currentNamespace, nil
+	for _, label := range podSecurityLabels {
+		labels[label] = string(level)
+	}
+}
+
+// Copies the PodSecurity labels of the wanted Namespace to the current Namespace
+// and removes them from the current Namespace, if not wanted anymore.
+// returns true if the current Namespace was changed
+func reconcilePodSecurityLabels(current, wanted *corev1.Namespace) (changed bool) {
+	for _, label := range podSecurityLabels {
+		wantedValue, wantedOk := wanted.Labels[label]
+		currentValue, currentOk := current.Labels[label]
+		if wantedOk == currentOk && wantedValue == currentValue {
+			continue
+		}
+
+		if !wantedOk {
+			delete(current.Labels, label)
+		} else {
+			if current.Labels == nil {
+				current.Labels = map[string]string{}
+			}
+			current.Labels[label] = wantedValue
+		}
+		changed = true
+	}
+	return changed
 }
 
 // Tests if the controller reference on `wanted` matches the one on `current`
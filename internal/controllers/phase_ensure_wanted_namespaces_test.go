@@ -9,8 +9,12 @@ import (
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
 	"github.com/openshift/addon-operator/internal/testutil"
 )
 
@@ -62,6 +66,7 @@ func TestEnsureWantedNamespaces_AddonWithSingleNamespace_NoCollision(t *testing.
 			Phase: corev1.NamespaceActive,
 		}
 	}).Return(nil)
+	c.StatusMock.On("Update", testutil.IsContext, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).Return(nil)
 
 	r := &AddonReconciler{
 		Client: c,
@@ -78,6 +83,133 @@ func TestEnsureWantedNamespaces_AddonWithSingleNamespace_NoCollision(t *testing.
 	c.AssertCalled(t, "Create", testutil.IsContext, testutil.IsCoreV1NamespacePtr, mock.Anything)
 }
 
+func TestEnsureWantedNamespaces_AddonWithSingleNamespace_Missing(t *testing.T) {
+	c := testutil.NewClient()
+	c.On("Get", mock.Anything, testutil.IsObjectKey, testutil.IsCoreV1NamespacePtr).Return(newTestErrNotFound())
+	c.On("Create", mock.Anything, testutil.IsCoreV1NamespacePtr, mock.Anything).Run(func(args mock.Arguments) {
+		arg := args.Get(1).(*corev1.Namespace)
+		arg.Status = corev1.NamespaceStatus{
+			Phase: corev1.NamespaceActive,
+		}
+	}).Return(nil)
+	c.StatusMock.On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).Return(nil)
+
+	r := &AddonReconciler{
+		Client: c,
+		Log:    testutil.NewLogger(t),
+		Scheme: newTestSchemeWithAddonsv1alpha1(),
+	}
+
+	// Namespace was created before, but the Addon never became Available
+	addon := newTestAddonWithSingleNamespace()
+	addon.Status.ObservedNamespaces = []string{"namespace-1"}
+
+	ctx := context.Background()
+	stop, err := r.ensureWantedNamespaces(ctx, addon)
+	require.NoError(t, err)
+	require.True(t, stop)
+	c.AssertExpectations(t)
+	c.AssertCalled(t, "Create", mock.Anything,
+		mock.MatchedBy(func(ns *corev1.Namespace) bool {
+			return ns.Name == "namespace-1"
+		}), mock.Anything)
+	c.StatusMock.AssertCalled(t, "Update", mock.Anything, addon, mock.Anything)
+
+	availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+	if assert.NotNil(t, availableCond) {
+		assert.Equal(t, metav1.ConditionFalse, availableCond.Status)
+		assert.Equal(t, "NamespaceMissing", availableCond.Reason)
+		assert.Contains(t, availableCond.Message, "namespace-1")
+	}
+	missingCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.NamespaceMissing)
+	if assert.NotNil(t, missingCond) {
+		assert.Equal(t, metav1.ConditionTrue, missingCond.Status)
+		assert.Equal(t, "NamespacesRecreated", missingCond.Reason)
+		assert.Contains(t, missingCond.Message, "namespace-1")
+	}
+	assert.Equal(t, addonsv1alpha1.PhasePending, addon.Status.Phase)
+}
+
+func TestEnsureWantedNamespaces_AddonWithSingleNamespace_MissingWithoutRecreation(t *testing.T) {
+	c := testutil.NewClient()
+	c.On("Get", mock.Anything, testutil.IsObjectKey, testutil.IsCoreV1NamespacePtr).Return(newTestErrNotFound())
+	c.StatusMock.On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).Return(nil)
+
+	r := &AddonReconciler{
+		Client: c,
+		Log:    testutil.NewLogger(t),
+		Scheme: newTestSchemeWithAddonsv1alpha1(),
+	}
+
+	addon := newTestAddonWithSingleNamespace()
+	addon.Spec.Namespaces[0].RecreationPolicy = addonsv1alpha1.NamespaceRecreationPolicyNever
+	addon.Status.ObservedNamespaces = []string{"namespace-1"}
+
+	ctx := context.Background()
+	stop, err := r.ensureWantedNamespaces(ctx, addon)
+	require.NoError(t, err)
+	require.True(t, stop)
+	c.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+
+	missingCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.NamespaceMissing)
+	if assert.NotNil(t, missingCond) {
+		assert.Equal(t, metav1.ConditionTrue, missingCond.Status)
+		assert.Equal(t, "NamespacesDeleted", missingCond.Reason)
+		assert.Contains(t, missingCond.Message, "namespace-1")
+	}
+	assert.False(t, meta.IsStatusConditionTrue(addon.Status.Conditions, addonsv1alpha1.Available))
+	// the Namespace is still reported as missing in the next reconcile
+	assert.Equal(t, []string{"namespace-1"}, addon.Status.ObservedNamespaces)
+}
+
+func TestEnsureWantedNamespaces_AddonWithSingleNamespace_MissingResolved(t *testing.T) {
+	r := &AddonReconciler{
+		Log:    testutil.NewLogger(t),
+		Scheme: newTestSchemeWithAddonsv1alpha1(),
+	}
+
+	addon := newTestAddonWithSingleNamespace()
+	addon.Status.ObservedNamespaces = []string{"namespace-1"}
+	meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+		Type:   addonsv1alpha1.NamespaceMissing,
+		Status: metav1.ConditionTrue,
+		Reason: "NamespacesRecreated",
+	})
+
+	existingNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "namespace-1",
+			Labels: map[string]string{},
+		},
+		Status: corev1.NamespaceStatus{
+			Phase: corev1.NamespaceActive,
+		},
+	}
+	addCommonLabels(existingNamespace.Labels, addon)
+	require.NoError(t, controllerutil.SetControllerReference(addon, existingNamespace, r.Scheme))
+
+	c := testutil.NewClient()
+	c.On("Get", mock.Anything, testutil.IsObjectKey, testutil.IsCoreV1NamespacePtr).Run(func(args mock.Arguments) {
+		arg := args.Get(2).(*corev1.Namespace)
+		existingNamespace.DeepCopyInto(arg)
+	}).Return(nil)
+	c.StatusMock.On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).Return(nil)
+	r.Client = c
+
+	ctx := context.Background()
+	stop, err := r.ensureWantedNamespaces(ctx, addon)
+	require.NoError(t, err)
+	require.False(t, stop)
+	c.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	c.StatusMock.AssertCalled(t, "Update", mock.Anything, addon, mock.Anything)
+
+	missingCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.NamespaceMissing)
+	if assert.NotNil(t, missingCond) {
+		assert.Equal(t, metav1.ConditionFalse, missingCond.Status)
+		assert.Equal(t, "NamespacesPresent", missingCond.Reason)
+	}
+}
+
 func TestEnsureWantedNamespaces_AddonWithMultipleNamespaces_NoCollision(t *testing.T) {
 	c := testutil.NewClient()
 	c.On("Get", testutil.IsContext, testutil.IsObjectKey, testutil.IsCoreV1NamespacePtr).Return(newTestErrNotFound())
@@ -87,6 +219,7 @@ func TestEnsureWantedNamespaces_AddonWithMultipleNamespaces_NoCollision(t *testi
 			Phase: corev1.NamespaceActive,
 		}
 	}).Return(nil)
+	c.StatusMock.On("Update", testutil.IsContext, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).Return(nil)
 
 	r := &AddonReconciler{
 		Client: c,
@@ -183,21 +316,127 @@ func TestEnsureNamespace_Create(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	ensuredNamespace, err := r.ensureNamespace(ctx, addon, addon.Spec.Namespaces[0].Name)
+	ensuredNamespace, _, err := r.ensureNamespace(ctx, addon, addon.Spec.Namespaces[0].Name)
 	c.AssertExpectations(t)
 	require.NoError(t, err)
 	require.NotNil(t, ensuredNamespace)
 }
 
+func TestEnsureNamespace_PodSecurityLabels(t *testing.T) {
+	for _, level := range []addonsv1alpha1.PodSecurityLevel{
+		addonsv1alpha1.PodSecurityLevelPrivileged,
+		addonsv1alpha1.PodSecurityLevelBaseline,
+		addonsv1alpha1.PodSecurityLevelRestricted,
+	} {
+		t.Run(string(level), func(t *testing.T) {
+			addon := newTestAddonWithSingleNamespace()
+			addon.Spec.PodSecurity = level
+
+			c := testutil.NewClient()
+			c.On("Get", mock.Anything, testutil.IsObjectKey, testutil.IsCoreV1NamespacePtr).
+				Return(newTestErrNotFound())
+			c.On("Create", mock.Anything, testutil.IsCoreV1NamespacePtr, mock.Anything).
+				Return(nil)
+
+			r := &AddonReconciler{
+				Client: c,
+				Log:    testutil.NewLogger(t),
+				Scheme: newTestSchemeWithAddonsv1alpha1(),
+			}
+
+			ctx := context.Background()
+			ensuredNamespace, _, err := r.ensureNamespace(ctx, addon, addon.Spec.Namespaces[0].Name)
+			require.NoError(t, err)
+			require.NotNil(t, ensuredNamespace)
+
+			assert.Equal(t, string(level), ensuredNamespace.Labels["pod-security.kubernetes.io/enforce"])
+			assert.Equal(t, string(level), ensuredNamespace.Labels["pod-security.kubernetes.io/warn"])
+			assert.Equal(t, string(level), ensuredNamespace.Labels["pod-security.kubernetes.io/audit"])
+		})
+	}
+}
+
+func TestReconcileNamespace_PodSecurityLabelDrift(t *testing.T) {
+	existingNamespace := newTestNamespace()
+	existingNamespace.Labels = map[string]string{
+		"pod-security.kubernetes.io/enforce": "privileged",
+		"pod-security.kubernetes.io/audit":   "baseline",
+		"some-other-label":                   "test",
+	}
+
+	t.Run("corrects drift", func(t *testing.T) {
+		c := testutil.NewClient()
+		c.On("Get", mock.Anything, testutil.IsObjectKey, testutil.IsCoreV1NamespacePtr).
+			Run(func(args mock.Arguments) {
+				existingNamespace.DeepCopyInto(args.Get(2).(*corev1.Namespace))
+			}).
+			Return(nil)
+		c.On("Update", mock.Anything, testutil.IsCoreV1NamespacePtr, mock.Anything).
+			Return(nil)
+
+		wantedNamespace := newTestNamespace()
+		wantedNamespace.Labels = map[string]string{}
+		addPodSecurityLabels(wantedNamespace.Labels, addonsv1alpha1.PodSecurityLevelRestricted)
+
+		ctx := context.Background()
+		reconciledNamespace, created, err := reconcileNamespace(ctx, c, wantedNamespace)
+		require.NoError(t, err)
+		assert.False(t, created)
+		c.AssertExpectations(t)
+
+		assert.Equal(t, map[string]string{
+			"pod-security.kubernetes.io/enforce": "restricted",
+			"pod-security.kubernetes.io/warn":    "restricted",
+			"pod-security.kubernetes.io/audit":   "restricted",
+			"some-other-label":                   "test",
+		}, reconciledNamespace.Labels)
+	})
+
+	t.Run("removes labels when unset", func(t *testing.T) {
+		c := testutil.NewClient()
+		c.On("Get", mock.Anything, testutil.IsObjectKey, testutil.IsCoreV1NamespacePtr).
+			Run(func(args mock.Arguments) {
+				existingNamespace.DeepCopyInto(args.Get(2).(*corev1.Namespace))
+			}).
+			Return(nil)
+		c.On("Update", mock.Anything, testutil.IsCoreV1NamespacePtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		reconciledNamespace, _, err := reconcileNamespace(ctx, c, newTestNamespace())
+		require.NoError(t, err)
+		c.AssertExpectations(t)
+
+		assert.Equal(t, map[string]string{
+			"some-other-label": "test",
+		}, reconciledNamespace.Labels)
+	})
+
+	t.Run("no-op without drift", func(t *testing.T) {
+		c := testutil.NewClient()
+		c.On("Get", mock.Anything, testutil.IsObjectKey, testutil.IsCoreV1NamespacePtr).
+			Run(func(args mock.Arguments) {
+				newTestNamespace().DeepCopyInto(args.Get(2).(*corev1.Namespace))
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		_, _, err := reconcileNamespace(ctx, c, newTestNamespace())
+		require.NoError(t, err)
+		c.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
 func TestReconcileNamespace_Create(t *testing.T) {
 	c := testutil.NewClient()
 	c.On("Get", testutil.IsContext, testutil.IsObjectKey, testutil.IsCoreV1NamespacePtr).Return(newTestErrNotFound())
 	c.On("Create", testutil.IsContext, testutil.IsCoreV1NamespacePtr, mock.Anything).Return(nil, newTestNamespace())
 
 	ctx := context.Background()
-	reconciledNamespace, err := reconcileNamespace(ctx, c, newTestNamespace())
+	reconciledNamespace, created, err := reconcileNamespace(ctx, c, newTestNamespace())
 	require.NoError(t, err)
 	assert.NotNil(t, reconciledNamespace)
+	assert.True(t, created)
 	assert.Equal(t, newTestNamespace(), reconciledNamespace)
 	c.AssertExpectations(t)
 	c.AssertCalled(t, "Get", testutil.IsContext, client.ObjectKey{
@@ -214,7 +453,7 @@ func TestReconcileNamespace_CreateWithCollisionWithoutOwner(t *testing.T) {
 	}).Return(nil)
 
 	ctx := context.Background()
-	_, err := reconcileNamespace(ctx, c, newTestNamespace())
+	_, _, err := reconcileNamespace(ctx, c, newTestNamespace())
 	require.EqualError(t, err, errNotOwnedByUs.Error())
 	c.AssertExpectations(t)
 	c.AssertCalled(t, "Get", testutil.IsContext, client.ObjectKey{
@@ -230,7 +469,7 @@ func TestReconcileNamespace_CreateWithCollisionWithOtherOwner(t *testing.T) {
 	}).Return(nil)
 
 	ctx := context.Background()
-	_, err := reconcileNamespace(ctx, c, newTestNamespace())
+	_, _, err := reconcileNamespace(ctx, c, newTestNamespace())
 	require.EqualError(t, err, errNotOwnedByUs.Error())
 	c.AssertExpectations(t)
 	c.AssertCalled(t, "Get", testutil.IsContext, client.ObjectKey{
@@ -246,7 +485,7 @@ func TestReconcileNamespace_CreateWithClientError(t *testing.T) {
 		Return(timeoutErr)
 
 	ctx := context.Background()
-	_, err := reconcileNamespace(ctx, c, newTestNamespace())
+	_, _, err := reconcileNamespace(ctx, c, newTestNamespace())
 	require.Error(t, err)
 	require.EqualError(t, err, timeoutErr.Error())
 	c.AssertExpectations(t)
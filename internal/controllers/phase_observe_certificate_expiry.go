@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+// Default time before expiry from which certificates are reported as expiring soon.
+const defaultCertificateExpiryWarningThreshold = 30 * 24 * time.Hour
+
+// Certificates are usually renewed out of band,
+// so expiring certificates are checked again periodically.
+const certificateExpiryRecheckInterval = time.Hour
+
+// Expiry of the earliest expiring certificate in a Secret.
+type certificateExpiry struct {
+	secretName string
+	notAfter   time.Time
+}
+
+// Reflects whether a watched certificate of the given Addon is about to expire
+// in the CertificateExpiringSoon status condition.
+// returns the duration after which the Addon needs to be reconciled again
+// to pick up certificates crossing the warning threshold
+func (r *AddonReconciler) observeCertificateExpiry(
+	ctx context.Context, addon *addonsv1alpha1.Addon) (requeueAfter time.Duration, err error) {
+	var expiries []certificateExpiry
+	if addon.Spec.CertificateExpiry != nil {
+		targetNamespace := getCommonInstallOptions(addon).Namespace
+		for _, secretName := range addon.Spec.CertificateExpiry.SecretNames {
+			secret := &corev1.Secret{}
+			err := r.apiReader().Get(ctx, client.ObjectKey{
+				Name:      secretName,
+				Namespace: targetNamespace,
+			}, secret)
+			if k8sApiErrors.IsNotFound(err) {
+				// the certificate may not be issued yet
+				continue
+			}
+			if err != nil {
+				return 0, fmt.Errorf("getting Secret: %w", err)
+			}
+
+			if notAfter, ok := earliestCertificateExpiry(secret.Data[corev1.TLSCertKey]); ok {
+				expiries = append(expiries, certificateExpiry{
+					secretName: secretName,
+					notAfter:   notAfter,
+				})
+			}
+		}
+	}
+
+	changed, requeueAfter := setCertificateExpiringSoonCondition(addon, expiries, time.Now())
+	if !changed {
+		return requeueAfter, nil
+	}
+	return requeueAfter, r.updateAddonStatus(ctx, addon)
+}
+
+// Returns the earliest expiry of all certificates in the given PEM data,
+// blocks that are no valid certificates are skipped.
+func earliestCertificateExpiry(data []byte) (notAfter time.Time, ok bool) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return notAfter, ok
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		if !ok || cert.NotAfter.Before(notAfter) {
+			notAfter = cert.NotAfter
+			ok = true
+		}
+	}
+}
+
+// Sets or removes the CertificateExpiringSoon condition depending on the given
+// certificate expiries in relation to the given point in time.
+func setCertificateExpiringSoonCondition(
+	addon *addonsv1alpha1.Addon, expiries []certificateExpiry, now time.Time) (
+	changed bool, requeueAfter time.Duration) {
+	if addon.Spec.CertificateExpiry == nil {
+		return removeCertificateExpiringSoonCondition(addon), 0
+	}
+
+	threshold := defaultCertificateExpiryWarningThreshold
+	if addon.Spec.CertificateExpiry.WarningThreshold != nil {
+		threshold = addon.Spec.CertificateExpiry.WarningThreshold.Duration
+	}
+
+	var expiring []string
+	for _, expiry := range expiries {
+		warnAt := expiry.notAfter.Add(-threshold)
+		if !now.Before(warnAt) {
+			expiring = append(expiring, fmt.Sprintf("%s (%s)",
+				expiry.secretName, expiry.notAfter.UTC().Format(time.RFC3339)))
+			continue
+		}
+		if untilWarning := warnAt.Sub(now); requeueAfter == 0 || untilWarning < requeueAfter {
+			requeueAfter = untilWarning
+		}
+	}
+
+	cond := metav1.Condition{
+		Type:               addonsv1alpha1.CertificateExpiringSoon,
+		Status:             metav1.ConditionFalse,
+		Reason:             "CertificatesValid",
+		Message:            "No watched certificate expires within " + threshold.String() + ".",
+		ObservedGeneration: addon.Generation,
+	}
+	if len(expiring) > 0 {
+		sort.Strings(expiring)
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "CertificateExpiringSoon"
+		cond.Message = "Certificates in Secrets expire soon: " + strings.Join(expiring, ", ") + "."
+		requeueAfter = certificateExpiryRecheckInterval
+	}
+
+	currentCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.CertificateExpiringSoon)
+	if currentCond != nil &&
+		currentCond.Status == cond.Status &&
+		currentCond.Reason == cond.Reason &&
+		currentCond.Message == cond.Message {
+		return false, requeueAfter
+	}
+	meta.SetStatusCondition(&addon.Status.Conditions, cond)
+	return true, requeueAfter
+}
+
+func removeCertificateExpiringSoonCondition(addon *addonsv1alpha1.Addon) (changed bool) {
+	if meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.CertificateExpiringSoon) == nil {
+		return false
+	}
+	meta.RemoveStatusCondition(&addon.Status.Conditions, addonsv1alpha1.CertificateExpiringSoon)
+	return true
+}
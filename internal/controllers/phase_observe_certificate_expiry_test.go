@@ -0,0 +1,197 @@
+package controllers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestObserveCertificateExpiry(t *testing.T) {
+	t.Run("near-expiry certificate sets condition", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithCertificateExpiry()
+
+		c.ExpectGet(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "serving-cert",
+				Namespace: "addon-1",
+			},
+			Data: map[string][]byte{
+				corev1.TLSCertKey: newTestCertificatePEM(t, time.Now().Add(24*time.Hour)),
+			},
+		})
+		c.ExpectStatusUpdate()
+
+		ctx := context.Background()
+		requeueAfter, err := r.observeCertificateExpiry(ctx, addon)
+		require.NoError(t, err)
+		assert.Equal(t, certificateExpiryRecheckInterval, requeueAfter)
+
+		c.StatusMock.AssertCalled(t, "Update", mock.Anything, addon, mock.Anything)
+		cond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.CertificateExpiringSoon)
+		if assert.NotNil(t, cond) {
+			assert.Equal(t, metav1.ConditionTrue, cond.Status)
+			assert.Equal(t, "CertificateExpiringSoon", cond.Reason)
+			assert.Contains(t, cond.Message, "serving-cert")
+		}
+	})
+
+	t.Run("missing Secret is not expiring", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithCertificateExpiry()
+
+		c.ExpectNotFound(client.ObjectKey{
+			Name:      "serving-cert",
+			Namespace: "addon-1",
+		}, &corev1.Secret{})
+		c.ExpectStatusUpdate()
+
+		ctx := context.Background()
+		requeueAfter, err := r.observeCertificateExpiry(ctx, addon)
+		require.NoError(t, err)
+		assert.Equal(t, time.Duration(0), requeueAfter)
+
+		assert.False(t, meta.IsStatusConditionTrue(
+			addon.Status.Conditions, addonsv1alpha1.CertificateExpiringSoon))
+	})
+}
+
+func TestSetCertificateExpiringSoonCondition(t *testing.T) {
+	now := time.Date(2021, time.April, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("fresh certificate clears condition", func(t *testing.T) {
+		addon := newTestAddonWithCertificateExpiry()
+		meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+			Type:   addonsv1alpha1.CertificateExpiringSoon,
+			Status: metav1.ConditionTrue,
+			Reason: "CertificateExpiringSoon",
+		})
+
+		changed, requeueAfter := setCertificateExpiringSoonCondition(addon, []certificateExpiry{
+			{secretName: "serving-cert", notAfter: now.Add(90 * 24 * time.Hour)},
+		}, now)
+		assert.True(t, changed)
+		// requeue when the certificate crosses the 30 day threshold
+		assert.Equal(t, 60*24*time.Hour, requeueAfter)
+
+		cond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.CertificateExpiringSoon)
+		if assert.NotNil(t, cond) {
+			assert.Equal(t, metav1.ConditionFalse, cond.Status)
+			assert.Equal(t, "CertificatesValid", cond.Reason)
+		}
+	})
+
+	t.Run("custom threshold", func(t *testing.T) {
+		addon := newTestAddonWithCertificateExpiry()
+		addon.Spec.CertificateExpiry.WarningThreshold = &metav1.Duration{Duration: 7 * 24 * time.Hour}
+
+		changed, _ := setCertificateExpiringSoonCondition(addon, []certificateExpiry{
+			{secretName: "serving-cert", notAfter: now.Add(10 * 24 * time.Hour)},
+		}, now)
+		assert.True(t, changed)
+		assert.False(t, meta.IsStatusConditionTrue(
+			addon.Status.Conditions, addonsv1alpha1.CertificateExpiringSoon))
+
+		changed, _ = setCertificateExpiringSoonCondition(addon, []certificateExpiry{
+			{secretName: "serving-cert", notAfter: now.Add(5 * 24 * time.Hour)},
+		}, now)
+		assert.True(t, changed)
+		assert.True(t, meta.IsStatusConditionTrue(
+			addon.Status.Conditions, addonsv1alpha1.CertificateExpiringSoon))
+	})
+
+	t.Run("unchanged condition", func(t *testing.T) {
+		addon := newTestAddonWithCertificateExpiry()
+		expiries := []certificateExpiry{
+			{secretName: "serving-cert", notAfter: now.Add(time.Hour)},
+		}
+
+		changed, _ := setCertificateExpiringSoonCondition(addon, expiries, now)
+		assert.True(t, changed)
+		changed, _ = setCertificateExpiringSoonCondition(addon, expiries, now)
+		assert.False(t, changed)
+	})
+
+	t.Run("removes condition without spec", func(t *testing.T) {
+		addon := newTestAddonWithCertificateExpiry()
+		setCertificateExpiringSoonCondition(addon, nil, now)
+		addon.Spec.CertificateExpiry = nil
+
+		changed, requeueAfter := setCertificateExpiringSoonCondition(addon, nil, now)
+		assert.True(t, changed)
+		assert.Equal(t, time.Duration(0), requeueAfter)
+		assert.Nil(t, meta.FindStatusCondition(
+			addon.Status.Conditions, addonsv1alpha1.CertificateExpiringSoon))
+	})
+}
+
+func TestEarliestCertificateExpiry(t *testing.T) {
+	leafNotAfter := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	caNotAfter := time.Now().Add(365 * 24 * time.Hour).Truncate(time.Second)
+
+	var data []byte
+	data = append(data, newTestCertificatePEM(t, caNotAfter)...)
+	data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("garbage")})...)
+	data = append(data, newTestCertificatePEM(t, leafNotAfter)...)
+
+	notAfter, ok := earliestCertificateExpiry(data)
+	assert.True(t, ok)
+	assert.True(t, leafNotAfter.Equal(notAfter))
+
+	_, ok = earliestCertificateExpiry([]byte("no certificate"))
+	assert.False(t, ok)
+}
+
+func newTestAddonWithCertificateExpiry() *addonsv1alpha1.Addon {
+	addon := testutil.NewAddon()
+	addon.Spec.CertificateExpiry = &addonsv1alpha1.AddonCertificateExpiry{
+		SecretNames: []string{"serving-cert"},
+	}
+	return addon
+}
+
+func newTestCertificatePEM(t *testing.T, notAfter time.Time) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "addon-1"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
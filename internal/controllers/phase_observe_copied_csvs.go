@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+// Label OLM puts on copied CSVs, pointing to the namespace of the original CSV.
+const olmCopiedFromLabel = "olm.copiedFrom"
+
+// Copied CSVs lag behind their source and events for them are not mapped to the Addon,
+// so out of sync copies are checked again periodically.
+const copiedCSVsOutOfSyncRecheckInterval = 30 * time.Second
+
+// Reflects whether OLM created an excessive number of copies of the given CSV,
+// or copies that are out of sync with it, in the ExcessiveCopiedCSVs status condition.
+// Only AllNamespaces Addons have their CSV copied into every namespace.
+// returns the duration after which the Addon needs to be reconciled again
+// to pick up copies catching up with the CSV
+func (r *AddonReconciler) observeCopiedCSVs(
+	ctx context.Context, addon *addonsv1alpha1.Addon,
+	csvKey client.ObjectKey,
+) (requeueAfter time.Duration, err error) {
+	if addon.Spec.Install.Type != addonsv1alpha1.OLMAllNamespaces {
+		return 0, nil
+	}
+
+	sourceCSV := &operatorsv1alpha1.ClusterServiceVersion{}
+	if err := r.Get(ctx, csvKey, sourceCSV); err != nil {
+		return 0, fmt.Errorf("getting CSV: %w", err)
+	}
+
+	csvs := &operatorsv1alpha1.ClusterServiceVersionList{}
+	if err := r.List(ctx, csvs, client.MatchingLabels{
+		olmCopiedFromLabel: csvKey.Namespace,
+	}); err != nil {
+		return 0, fmt.Errorf("could not list copied CSVs: %w", err)
+	}
+
+	// other operators in the same namespace have their CSVs copied as well
+	var copiedCSVs, outOfSyncCSVs int
+	for i := range csvs.Items {
+		copiedCSV := &csvs.Items[i]
+		if isCopiedCSVOutOfSync(sourceCSV, copiedCSV) {
+			outOfSyncCSVs++
+		}
+		if copiedCSV.Name == csvKey.Name {
+			copiedCSVs++
+		}
+	}
+
+	if outOfSyncCSVs > 0 {
+		requeueAfter = copiedCSVsOutOfSyncRecheckInterval
+	}
+	if !r.setExcessiveCopiedCSVsCondition(addon, csvKey.Name, copiedCSVs, outOfSyncCSVs) {
+		return requeueAfter, nil
+	}
+	return requeueAfter, r.updateAddonStatus(ctx, addon)
+}
+
+// Checks whether the given copied CSV disagrees with the given source CSV.
+// Copies of the CSV that was replaced by the source CSV should have been
+// garbage collected by OLM, copies of the source CSV mirror its phase.
+func isCopiedCSVOutOfSync(
+	sourceCSV, copiedCSV *operatorsv1alpha1.ClusterServiceVersion) bool {
+	if len(sourceCSV.Spec.Replaces) > 0 && copiedCSV.Name == sourceCSV.Spec.Replaces {
+		return true
+	}
+	return copiedCSV.Name == sourceCSV.Name &&
+		copiedCSV.Status.Phase != sourceCSV.Status.Phase
+}
+
+// Sets the ExcessiveCopiedCSVs condition according to the given number
+// of copied and out of sync CSVs.
+// The message does not contain the live numbers,
+// so it does not change for every copied CSV that is added or removed.
+func (r *AddonReconciler) setExcessiveCopiedCSVsCondition(
+	addon *addonsv1alpha1.Addon, csvName string, copiedCSVs, outOfSyncCSVs int) (changed bool) {
+	threshold := r.copiedCSVThreshold()
+
+	cond := metav1.Condition{
+		Type:   addonsv1alpha1.ExcessiveCopiedCSVs,
+		Status: metav1.ConditionFalse,
+		Reason: "CopiedCSVsWithinThreshold",
+		Message: fmt.Sprintf("Copies of CSV %s are within the threshold of %d.",
+			csvName, threshold),
+		ObservedGeneration: addon.Generation,
+	}
+	switch {
+	case copiedCSVs > threshold:
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "CopiedCSVThresholdExceeded"
+		cond.Message = fmt.Sprintf("Copies of CSV %s exceed the threshold of %d.",
+			csvName, threshold)
+	case outOfSyncCSVs > 0:
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "CopiedCSVsOutOfSync"
+		cond.Message = fmt.Sprintf("Copied CSVs are stale or out of sync with CSV %s.",
+			csvName)
+	}
+
+	currentCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.ExcessiveCopiedCSVs)
+	if currentCond != nil &&
+		currentCond.Status == cond.Status &&
+		currentCond.Reason == cond.Reason &&
+		currentCond.Message == cond.Message {
+		return false
+	}
+	meta.SetStatusCondition(&addon.Status.Conditions, cond)
+	return true
+}
+
+// Returns the configured copied CSV threshold or the default.
+func (r *AddonReconciler) copiedCSVThreshold() int {
+	if r.CopiedCSVThreshold <= 0 {
+		return DefaultCopiedCSVThreshold
+	}
+	return r.CopiedCSVThreshold
+}
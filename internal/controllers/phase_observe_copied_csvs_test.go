@@ -0,0 +1,293 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestObserveCopiedCSVs(t *testing.T) {
+	csvKey := client.ObjectKey{
+		Name:      "addon-1.v1.0.0",
+		Namespace: "openshift-operators",
+	}
+
+	tests := []struct {
+		name           string
+		copiedCSVs     int
+		expectedStatus metav1.ConditionStatus
+		expectedReason string
+	}{
+		{
+			name:           "excessive count",
+			copiedCSVs:     4,
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: "CopiedCSVThresholdExceeded",
+		},
+		{
+			name:           "normal count",
+			copiedCSVs:     3,
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: "CopiedCSVsWithinThreshold",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := testutil.NewClient()
+			r := &AddonReconciler{
+				Client:             c,
+				Scheme:             newTestSchemeWithAddonsv1alpha1(),
+				CopiedCSVThreshold: 3,
+			}
+
+			addon := newTestAddonWithAllNamespaces()
+			// previously reported as excessive
+			meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+				Type:   addonsv1alpha1.ExcessiveCopiedCSVs,
+				Status: metav1.ConditionTrue,
+				Reason: "CopiedCSVThresholdExceeded",
+			})
+
+			mockGetSourceCSV(c, csvKey)
+			c.On("List", mock.Anything,
+				mock.IsType(&operatorsv1alpha1.ClusterServiceVersionList{}), mock.Anything).
+				Run(func(args mock.Arguments) {
+					list := args.Get(1).(*operatorsv1alpha1.ClusterServiceVersionList)
+					for i := 0; i < test.copiedCSVs; i++ {
+						list.Items = append(list.Items,
+							newTestCopiedCSV(csvKey.Name, i, operatorsv1alpha1.CSVPhaseSucceeded))
+					}
+					// copies of other operators are not counted
+					list.Items = append(list.Items, operatorsv1alpha1.ClusterServiceVersion{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "other-operator.v1.0.0",
+							Namespace: "namespace-0",
+						},
+					})
+				}).
+				Return(nil)
+			c.StatusMock.
+				On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+				Return(nil)
+
+			ctx := context.Background()
+			requeueAfter, err := r.observeCopiedCSVs(ctx, addon, csvKey)
+			require.NoError(t, err)
+			assert.Equal(t, time.Duration(0), requeueAfter)
+
+			c.AssertCalled(t, "List", mock.Anything, mock.Anything,
+				[]client.ListOption{
+					client.MatchingLabels{olmCopiedFromLabel: "openshift-operators"},
+				})
+			cond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.ExcessiveCopiedCSVs)
+			if assert.NotNil(t, cond) {
+				assert.Equal(t, test.expectedStatus, cond.Status)
+				assert.Equal(t, test.expectedReason, cond.Reason)
+			}
+		})
+	}
+
+	outOfSyncTests := []struct {
+		name      string
+		copiedCSV operatorsv1alpha1.ClusterServiceVersion
+	}{
+		{
+			name:      "stale copy of replaced CSV",
+			copiedCSV: newTestCopiedCSV("addon-1.v0.9.0", 0, operatorsv1alpha1.CSVPhaseSucceeded),
+		},
+		{
+			name:      "copy with diverging phase",
+			copiedCSV: newTestCopiedCSV(csvKey.Name, 0, operatorsv1alpha1.CSVPhaseFailed),
+		},
+	}
+	for _, test := range outOfSyncTests {
+		t.Run(test.name, func(t *testing.T) {
+			c := testutil.NewClient()
+			r := &AddonReconciler{
+				Client: c,
+				Scheme: newTestSchemeWithAddonsv1alpha1(),
+			}
+
+			addon := newTestAddonWithAllNamespaces()
+
+			mockGetSourceCSV(c, csvKey)
+			c.On("List", mock.Anything,
+				mock.IsType(&operatorsv1alpha1.ClusterServiceVersionList{}), mock.Anything).
+				Run(func(args mock.Arguments) {
+					list := args.Get(1).(*operatorsv1alpha1.ClusterServiceVersionList)
+					list.Items = append(list.Items, test.copiedCSV)
+				}).
+				Return(nil)
+			c.StatusMock.
+				On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+				Return(nil)
+
+			ctx := context.Background()
+			requeueAfter, err := r.observeCopiedCSVs(ctx, addon, csvKey)
+			require.NoError(t, err)
+			assert.Equal(t, copiedCSVsOutOfSyncRecheckInterval, requeueAfter)
+
+			cond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.ExcessiveCopiedCSVs)
+			if assert.NotNil(t, cond) {
+				assert.Equal(t, metav1.ConditionTrue, cond.Status)
+				assert.Equal(t, "CopiedCSVsOutOfSync", cond.Reason)
+			}
+		})
+	}
+
+	t.Run("ignores OwnNamespace Addons", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		ctx := context.Background()
+		_, err := r.observeCopiedCSVs(ctx, newTestAddonWithCatalogSourceImage(), csvKey)
+		require.NoError(t, err)
+		c.AssertNotCalled(t, "List", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unchanged condition is not written again", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithAllNamespaces()
+		mockGetSourceCSV(c, csvKey)
+		// more namespaces got created in between
+		c.On("List", mock.Anything,
+			mock.IsType(&operatorsv1alpha1.ClusterServiceVersionList{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				list := args.Get(1).(*operatorsv1alpha1.ClusterServiceVersionList)
+				list.Items = append(list.Items,
+					newTestCopiedCSV(csvKey.Name, 0, operatorsv1alpha1.CSVPhaseSucceeded))
+			}).
+			Return(nil).
+			Once()
+		c.On("List", mock.Anything,
+			mock.IsType(&operatorsv1alpha1.ClusterServiceVersionList{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				list := args.Get(1).(*operatorsv1alpha1.ClusterServiceVersionList)
+				list.Items = append(list.Items,
+					newTestCopiedCSV(csvKey.Name, 0, operatorsv1alpha1.CSVPhaseSucceeded),
+					newTestCopiedCSV(csvKey.Name, 1, operatorsv1alpha1.CSVPhaseSucceeded))
+			}).
+			Return(nil)
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		_, err := r.observeCopiedCSVs(ctx, addon, csvKey)
+		require.NoError(t, err)
+		_, err = r.observeCopiedCSVs(ctx, addon, csvKey)
+		require.NoError(t, err)
+		c.StatusMock.AssertNumberOfCalls(t, "Update", 1)
+	})
+
+	t.Run("out of sync copies clear once caught up", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithAllNamespaces()
+		mockGetSourceCSV(c, csvKey)
+		// the copy still mirrors the previous phase of the source CSV
+		c.On("List", mock.Anything,
+			mock.IsType(&operatorsv1alpha1.ClusterServiceVersionList{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				list := args.Get(1).(*operatorsv1alpha1.ClusterServiceVersionList)
+				list.Items = append(list.Items,
+					newTestCopiedCSV(csvKey.Name, 0, operatorsv1alpha1.CSVPhaseInstalling))
+			}).
+			Return(nil).
+			Once()
+		c.On("List", mock.Anything,
+			mock.IsType(&operatorsv1alpha1.ClusterServiceVersionList{}), mock.Anything).
+			Run(func(args mock.Arguments) {
+				list := args.Get(1).(*operatorsv1alpha1.ClusterServiceVersionList)
+				list.Items = append(list.Items,
+					newTestCopiedCSV(csvKey.Name, 0, operatorsv1alpha1.CSVPhaseSucceeded))
+			}).
+			Return(nil)
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		requeueAfter, err := r.observeCopiedCSVs(ctx, addon, csvKey)
+		require.NoError(t, err)
+		assert.Equal(t, copiedCSVsOutOfSyncRecheckInterval, requeueAfter)
+		assert.True(t, meta.IsStatusConditionTrue(
+			addon.Status.Conditions, addonsv1alpha1.ExcessiveCopiedCSVs))
+
+		// reconciled again after the requeue
+		requeueAfter, err = r.observeCopiedCSVs(ctx, addon, csvKey)
+		require.NoError(t, err)
+		assert.Equal(t, time.Duration(0), requeueAfter)
+		cond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.ExcessiveCopiedCSVs)
+		if assert.NotNil(t, cond) {
+			assert.Equal(t, metav1.ConditionFalse, cond.Status)
+			assert.Equal(t, "CopiedCSVsWithinThreshold", cond.Reason)
+		}
+	})
+}
+
+func mockGetSourceCSV(c *testutil.Client, csvKey client.ObjectKey) {
+	c.On("Get", mock.Anything, csvKey,
+		mock.IsType(&operatorsv1alpha1.ClusterServiceVersion{})).
+		Run(func(args mock.Arguments) {
+			csv := args.Get(2).(*operatorsv1alpha1.ClusterServiceVersion)
+			csv.Name = csvKey.Name
+			csv.Namespace = csvKey.Namespace
+			csv.Spec.Replaces = "addon-1.v0.9.0"
+			csv.Status.Phase = operatorsv1alpha1.CSVPhaseSucceeded
+		}).
+		Return(nil)
+}
+
+func newTestCopiedCSV(
+	name string, namespaceIndex int, phase operatorsv1alpha1.ClusterServiceVersionPhase,
+) operatorsv1alpha1.ClusterServiceVersion {
+	return operatorsv1alpha1.ClusterServiceVersion{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: fmt.Sprintf("namespace-%d", namespaceIndex),
+		},
+		Status: operatorsv1alpha1.ClusterServiceVersionStatus{
+			Phase: phase,
+		},
+	}
+}
+
+func newTestAddonWithAllNamespaces() *addonsv1alpha1.Addon {
+	addon := newTestAddonWithCatalogSourceImage()
+	addon.Spec.Install = addonsv1alpha1.AddonInstallSpec{
+		Type: addonsv1alpha1.OLMAllNamespaces,
+		OLMAllNamespaces: &addonsv1alpha1.AddonInstallOLMAllNamespaces{
+			AddonInstallOLMCommon: addonsv1alpha1.AddonInstallOLMCommon{
+				CatalogSourceImage: addon.Spec.Install.OLMOwnNamespace.CatalogSourceImage,
+				Namespace:          "openshift-operators",
+			},
+		},
+	}
+	return addon
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -12,40 +13,189 @@ import (
 	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
 )
 
+// Reports the readiness of the CSV OLM installs for the given Addon.
+// The CSV of the channel head only exists once OLM starts installing it,
+// until then the installed CSV is observed.
 func (r *AddonReconciler) observeCurrentCSV(
 	ctx context.Context,
 	addon *addonsv1alpha1.Addon,
-	csvKey client.ObjectKey,
+	installedCSVKey, currentCSVKey client.ObjectKey,
 ) (requeue bool, err error) {
-	csv := &operatorsv1alpha1.ClusterServiceVersion{}
-	if err := r.Get(ctx, csvKey, csv); err != nil {
+	installedCSV := &operatorsv1alpha1.ClusterServiceVersion{}
+	err = r.Get(ctx, installedCSVKey, installedCSV)
+	if k8sApiErrors.IsNotFound(err) {
+		if !meta.IsStatusConditionTrue(addon.Status.Conditions, addonsv1alpha1.Available) {
+			// OLM has not installed the CSV yet
+			return true, r.reportUnreadyCSV(ctx, addon, "UnreadyCSV", "not found")
+		}
+
+		// the cache may not have seen a CSV that was just installed by an upgrade yet
+		err = r.apiReader().Get(ctx, installedCSVKey, installedCSV)
+		if k8sApiErrors.IsNotFound(err) {
+			// the CSV was deleted out-of-band
+			return true, r.reportOrphanedAddon(ctx, addon, installedCSVKey)
+		}
+	}
+	if err != nil {
 		return false, fmt.Errorf("getting installed CSV: %w", err)
 	}
 
+	csv := installedCSV
+	if currentCSVKey != installedCSVKey {
+		currentCSV := &operatorsv1alpha1.ClusterServiceVersion{}
+		err = r.Get(ctx, currentCSVKey, currentCSV)
+		switch {
+		case k8sApiErrors.IsNotFound(err):
+			// the upgrade has not started yet
+		case err != nil:
+			return false, fmt.Errorf("getting current CSV: %w", err)
+		default:
+			csv = currentCSV
+		}
+	}
+
+	replacing, err := r.isCSVUpgradeInProgress(ctx, installedCSV, csv)
+	if err != nil {
+		return false, err
+	}
+
 	var message string
-	switch csv.Status.Phase {
-	case operatorsv1alpha1.CSVPhaseSucceeded:
+	reason := "UnreadyCSV"
+	switch phase := csv.Status.Phase; {
+	case replacing:
+		message = "replacing"
+		reason = addonsv1alpha1.AddonReasonCSVReplacing
+	case phase == operatorsv1alpha1.CSVPhaseSucceeded:
 		// do nothing here
-	case operatorsv1alpha1.CSVPhaseFailed:
+	case phase == operatorsv1alpha1.CSVPhaseFailed:
 		message = "failed"
+	case phase == operatorsv1alpha1.CSVPhasePending:
+		message = "pending"
+		reason = addonsv1alpha1.AddonReasonCSVPending
 	default:
 		message = "unkown/pending"
 	}
 
 	if message != "" {
-		meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
-			Type:   addonsv1alpha1.Available,
-			Status: metav1.ConditionFalse,
-			Reason: "UnreadyCSV",
-			Message: fmt.Sprintf(
-				"ClusterServiceVersion is not ready: %s",
-				message),
-			ObservedGeneration: addon.Generation,
-		})
-		addon.Status.ObservedGeneration = addon.Generation
-		addon.Status.Phase = addonsv1alpha1.PhasePending
-		return true, r.Status().Update(ctx, addon)
+		setOrphanedConditionRestored(addon)
+		return true, r.reportUnreadyCSV(ctx, addon, reason, message)
 	}
 
+	if setOrphanedConditionRestored(addon) {
+		return false, r.updateAddonStatus(ctx, addon)
+	}
 	return false, nil
 }
+
+// OLM moves the CSV that is upgraded from into the Replacing phase,
+// while the CSV replacing it is still installing.
+func (r *AddonReconciler) isCSVUpgradeInProgress(
+	ctx context.Context,
+	installedCSV, currentCSV *operatorsv1alpha1.ClusterServiceVersion,
+) (bool, error) {
+	if installedCSV.Status.Phase == operatorsv1alpha1.CSVPhaseReplacing {
+		return true, nil
+	}
+	if currentCSV.Status.Phase == operatorsv1alpha1.CSVPhaseSucceeded ||
+		len(currentCSV.Spec.Replaces) == 0 {
+		return false, nil
+	}
+
+	replacedCSV := &operatorsv1alpha1.ClusterServiceVersion{}
+	err := r.Get(ctx, client.ObjectKey{
+		Name:      currentCSV.Spec.Replaces,
+		Namespace: currentCSV.Namespace,
+	}, replacedCSV)
+	if k8sApiErrors.IsNotFound(err) {
+		// the replaced CSV is already gone
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("getting replaced CSV: %w", err)
+	}
+	return replacedCSV.Status.Phase == operatorsv1alpha1.CSVPhaseReplacing ||
+		replacedCSV.Status.Phase == operatorsv1alpha1.CSVPhaseDeleting, nil
+}
+
+func (r *AddonReconciler) reportUnreadyCSV(
+	ctx context.Context,
+	addon *addonsv1alpha1.Addon,
+	reason, message string,
+) error {
+	meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+		Type:   addonsv1alpha1.Available,
+		Status: metav1.ConditionFalse,
+		Reason: reason,
+		Message: fmt.Sprintf(
+			"ClusterServiceVersion is not ready: %s",
+			message),
+		ObservedGeneration: addon.Generation,
+	})
+	addon.Status.ObservedGeneration = addon.Generation
+	addon.Status.Phase = addonsv1alpha1.PhasePending
+	if err := r.suppressUnavailableDuringMaintenance(ctx, addon); err != nil {
+		return err
+	}
+	return r.updateAddonStatus(ctx, addon)
+}
+
+// Reports the given Addon as orphaned.
+// Unless the OrphanedCSVPolicy is Never, its Subscription is deleted,
+// so it is recreated in the next reconcile and OLM installs the CSV again.
+func (r *AddonReconciler) reportOrphanedAddon(
+	ctx context.Context,
+	addon *addonsv1alpha1.Addon,
+	csvKey client.ObjectKey,
+) error {
+	message := fmt.Sprintf(
+		"ClusterServiceVersion %s is missing.", csvKey.Name)
+	if addon.Spec.OrphanedCSVPolicy != addonsv1alpha1.OrphanedCSVPolicyNever {
+		subscription := &operatorsv1alpha1.Subscription{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      addon.Name,
+				Namespace: getCommonInstallOptions(addon).Namespace,
+			},
+		}
+		err := r.Delete(ctx, subscription)
+		// don't propagate error if the Subscription is already gone
+		if err != nil && !k8sApiErrors.IsNotFound(err) {
+			return fmt.Errorf("deleting Subscription: %w", err)
+		}
+		message = fmt.Sprintf(
+			"ClusterServiceVersion %s is missing, reinstalling.", csvKey.Name)
+	}
+
+	meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+		Type:               addonsv1alpha1.Orphaned,
+		Status:             metav1.ConditionTrue,
+		Reason:             "CSVMissing",
+		Message:            message,
+		ObservedGeneration: addon.Generation,
+	})
+	meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+		Type:               addonsv1alpha1.Available,
+		Status:             metav1.ConditionFalse,
+		Reason:             "UnreadyCSV",
+		Message:            message,
+		ObservedGeneration: addon.Generation,
+	})
+	addon.Status.ObservedGeneration = addon.Generation
+	addon.Status.Phase = addonsv1alpha1.PhasePending
+	return r.updateAddonStatus(ctx, addon)
+}
+
+// Clears the Orphaned condition of the given Addon after its CSV is present again.
+// Addons that never lost their CSV don't carry the condition at all.
+func setOrphanedConditionRestored(addon *addonsv1alpha1.Addon) (changed bool) {
+	if !meta.IsStatusConditionTrue(addon.Status.Conditions, addonsv1alpha1.Orphaned) {
+		return false
+	}
+	meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+		Type:               addonsv1alpha1.Orphaned,
+		Status:             metav1.ConditionFalse,
+		Reason:             "CSVRestored",
+		Message:            "ClusterServiceVersion is present.",
+		ObservedGeneration: addon.Generation,
+	})
+	return true
+}
@@ -0,0 +1,404 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestObserveCurrentCSV(t *testing.T) {
+	csvKey := client.ObjectKey{
+		Name:      "addon-1.v1.0.0",
+		Namespace: "addon-1",
+	}
+
+	tests := []struct {
+		name           string
+		phase          operatorsv1alpha1.ClusterServiceVersionPhase
+		expectedReason string
+	}{
+		{
+			name:           "replacing",
+			phase:          operatorsv1alpha1.CSVPhaseReplacing,
+			expectedReason: addonsv1alpha1.AddonReasonCSVReplacing,
+		},
+		{
+			name:           "pending",
+			phase:          operatorsv1alpha1.CSVPhasePending,
+			expectedReason: addonsv1alpha1.AddonReasonCSVPending,
+		},
+		{
+			name:           "failed",
+			phase:          operatorsv1alpha1.CSVPhaseFailed,
+			expectedReason: "UnreadyCSV",
+		},
+		{
+			name:           "installing",
+			phase:          operatorsv1alpha1.CSVPhaseInstalling,
+			expectedReason: "UnreadyCSV",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := testutil.NewClient()
+			r := &AddonReconciler{
+				Client: c,
+				Scheme: newTestSchemeWithAddonsv1alpha1(),
+			}
+
+			addon := newTestAddonWithCatalogSourceImage()
+
+			c.On("Get", mock.Anything, csvKey,
+				mock.IsType(&operatorsv1alpha1.ClusterServiceVersion{})).
+				Run(func(args mock.Arguments) {
+					csv := args.Get(2).(*operatorsv1alpha1.ClusterServiceVersion)
+					csv.Status.Phase = test.phase
+				}).
+				Return(nil)
+			c.StatusMock.
+				On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+				Return(nil)
+
+			ctx := context.Background()
+			requeue, err := r.observeCurrentCSV(ctx, addon, csvKey, csvKey)
+			require.NoError(t, err)
+			assert.True(t, requeue)
+
+			availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+			if assert.NotNil(t, availableCond) {
+				assert.Equal(t, metav1.ConditionFalse, availableCond.Status)
+				assert.Equal(t, test.expectedReason, availableCond.Reason)
+			}
+			assert.Equal(t, addonsv1alpha1.PhasePending, addon.Status.Phase)
+		})
+	}
+
+	t.Run("succeeded", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithCatalogSourceImage()
+
+		c.On("Get", mock.Anything, csvKey,
+			mock.IsType(&operatorsv1alpha1.ClusterServiceVersion{})).
+			Run(func(args mock.Arguments) {
+				csv := args.Get(2).(*operatorsv1alpha1.ClusterServiceVersion)
+				csv.Status.Phase = operatorsv1alpha1.CSVPhaseSucceeded
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		requeue, err := r.observeCurrentCSV(ctx, addon, csvKey, csvKey)
+		require.NoError(t, err)
+		assert.False(t, requeue)
+
+		// Available is reported by the last phase once everything else is in place
+		c.StatusMock.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing CSV reinstalls orphaned Addon", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithCatalogSourceImage()
+		meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+			Type:   addonsv1alpha1.Available,
+			Status: metav1.ConditionTrue,
+			Reason: "FullyReconciled",
+		})
+
+		c.On("Get", mock.Anything, csvKey,
+			mock.IsType(&operatorsv1alpha1.ClusterServiceVersion{})).
+			Return(newTestErrNotFound())
+		c.On("Delete", mock.Anything,
+			mock.IsType(&operatorsv1alpha1.Subscription{}), mock.Anything).
+			Return(nil)
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		requeue, err := r.observeCurrentCSV(ctx, addon, csvKey, csvKey)
+		require.NoError(t, err)
+		assert.True(t, requeue)
+
+		c.AssertCalled(t, "Delete", mock.Anything,
+			mock.MatchedBy(func(sub *operatorsv1alpha1.Subscription) bool {
+				return sub.Name == addon.Name && sub.Namespace == "addon-1"
+			}), mock.Anything)
+		orphanedCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Orphaned)
+		if assert.NotNil(t, orphanedCond) {
+			assert.Equal(t, metav1.ConditionTrue, orphanedCond.Status)
+			assert.Equal(t, "CSVMissing", orphanedCond.Reason)
+			assert.Contains(t, orphanedCond.Message, csvKey.Name)
+		}
+		assert.False(t, meta.IsStatusConditionTrue(addon.Status.Conditions, addonsv1alpha1.Available))
+	})
+
+	t.Run("CSV missing from cache is not reinstalled", func(t *testing.T) {
+		c := testutil.NewClient()
+		apiReader := testutil.NewClient()
+		r := &AddonReconciler{
+			Client:    c,
+			APIReader: apiReader,
+			Scheme:    newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithCatalogSourceImage()
+		meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+			Type:   addonsv1alpha1.Available,
+			Status: metav1.ConditionTrue,
+			Reason: "FullyReconciled",
+		})
+
+		c.On("Get", mock.Anything, csvKey,
+			mock.IsType(&operatorsv1alpha1.ClusterServiceVersion{})).
+			Return(newTestErrNotFound())
+		apiReader.On("Get", mock.Anything, csvKey,
+			mock.IsType(&operatorsv1alpha1.ClusterServiceVersion{})).
+			Run(func(args mock.Arguments) {
+				csv := args.Get(2).(*operatorsv1alpha1.ClusterServiceVersion)
+				csv.Status.Phase = operatorsv1alpha1.CSVPhaseSucceeded
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		requeue, err := r.observeCurrentCSV(ctx, addon, csvKey, csvKey)
+		require.NoError(t, err)
+		assert.False(t, requeue)
+
+		apiReader.AssertExpectations(t)
+		c.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+		assert.Nil(t, meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Orphaned))
+		assert.True(t, meta.IsStatusConditionTrue(addon.Status.Conditions, addonsv1alpha1.Available))
+	})
+
+	t.Run("missing CSV is only reported with policy Never", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithCatalogSourceImage()
+		addon.Spec.OrphanedCSVPolicy = addonsv1alpha1.OrphanedCSVPolicyNever
+		meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+			Type:   addonsv1alpha1.Available,
+			Status: metav1.ConditionTrue,
+			Reason: "FullyReconciled",
+		})
+
+		c.On("Get", mock.Anything, csvKey,
+			mock.IsType(&operatorsv1alpha1.ClusterServiceVersion{})).
+			Return(newTestErrNotFound())
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		requeue, err := r.observeCurrentCSV(ctx, addon, csvKey, csvKey)
+		require.NoError(t, err)
+		assert.True(t, requeue)
+
+		c.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+		orphanedCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Orphaned)
+		if assert.NotNil(t, orphanedCond) {
+			assert.Equal(t, metav1.ConditionTrue, orphanedCond.Status)
+			assert.Equal(t, "CSVMissing", orphanedCond.Reason)
+			assert.NotContains(t, orphanedCond.Message, "reinstalling")
+		}
+		assert.False(t, meta.IsStatusConditionTrue(addon.Status.Conditions, addonsv1alpha1.Available))
+	})
+
+	t.Run("missing CSV of never available Addon is not reinstalled", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithCatalogSourceImage()
+
+		c.On("Get", mock.Anything, csvKey,
+			mock.IsType(&operatorsv1alpha1.ClusterServiceVersion{})).
+			Return(newTestErrNotFound())
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		requeue, err := r.observeCurrentCSV(ctx, addon, csvKey, csvKey)
+		require.NoError(t, err)
+		assert.True(t, requeue)
+
+		c.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+		assert.Nil(t, meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Orphaned))
+		availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+		if assert.NotNil(t, availableCond) {
+			assert.Equal(t, metav1.ConditionFalse, availableCond.Status)
+			assert.Equal(t, "UnreadyCSV", availableCond.Reason)
+		}
+	})
+
+	t.Run("missing current CSV keeps Subscription", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithCatalogSourceImage()
+		meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+			Type:   addonsv1alpha1.Available,
+			Status: metav1.ConditionTrue,
+			Reason: "FullyReconciled",
+		})
+		currentCSVKey := client.ObjectKey{
+			Name:      "addon-1.v1.1.0",
+			Namespace: "addon-1",
+		}
+
+		c.On("Get", mock.Anything, csvKey,
+			mock.IsType(&operatorsv1alpha1.ClusterServiceVersion{})).
+			Run(func(args mock.Arguments) {
+				csv := args.Get(2).(*operatorsv1alpha1.ClusterServiceVersion)
+				csv.Status.Phase = operatorsv1alpha1.CSVPhaseSucceeded
+			}).
+			Return(nil)
+		c.On("Get", mock.Anything, currentCSVKey,
+			mock.IsType(&operatorsv1alpha1.ClusterServiceVersion{})).
+			Return(newTestErrNotFound())
+
+		ctx := context.Background()
+		requeue, err := r.observeCurrentCSV(ctx, addon, csvKey, currentCSVKey)
+		require.NoError(t, err)
+		assert.False(t, requeue)
+
+		c.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+		assert.Nil(t, meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Orphaned))
+		c.StatusMock.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("upgrade in progress", func(t *testing.T) {
+		oldCSVKey := csvKey
+		newCSVKey := client.ObjectKey{
+			Name:      "addon-1.v1.1.0",
+			Namespace: "addon-1",
+		}
+
+		tests := []struct {
+			name            string
+			installedCSVKey client.ObjectKey
+		}{
+			{
+				// the Subscription still points at the old CSV
+				name:            "installed CSV is replaced",
+				installedCSVKey: oldCSVKey,
+			},
+			{
+				// the Subscription already points at the new CSV
+				name:            "installed CSV replaces",
+				installedCSVKey: newCSVKey,
+			},
+		}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				c := testutil.NewClient()
+				r := &AddonReconciler{
+					Client: c,
+					Scheme: newTestSchemeWithAddonsv1alpha1(),
+				}
+
+				addon := newTestAddonWithCatalogSourceImage()
+
+				c.On("Get", mock.Anything, oldCSVKey,
+					mock.IsType(&operatorsv1alpha1.ClusterServiceVersion{})).
+					Run(func(args mock.Arguments) {
+						csv := args.Get(2).(*operatorsv1alpha1.ClusterServiceVersion)
+						csv.Name = oldCSVKey.Name
+						csv.Namespace = oldCSVKey.Namespace
+						csv.Status.Phase = operatorsv1alpha1.CSVPhaseReplacing
+					}).
+					Return(nil)
+				c.On("Get", mock.Anything, newCSVKey,
+					mock.IsType(&operatorsv1alpha1.ClusterServiceVersion{})).
+					Run(func(args mock.Arguments) {
+						csv := args.Get(2).(*operatorsv1alpha1.ClusterServiceVersion)
+						csv.Name = newCSVKey.Name
+						csv.Namespace = newCSVKey.Namespace
+						csv.Spec.Replaces = oldCSVKey.Name
+						csv.Status.Phase = operatorsv1alpha1.CSVPhaseInstalling
+					}).
+					Return(nil)
+				c.StatusMock.
+					On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+					Return(nil)
+
+				ctx := context.Background()
+				requeue, err := r.observeCurrentCSV(ctx, addon, test.installedCSVKey, newCSVKey)
+				require.NoError(t, err)
+				assert.True(t, requeue)
+
+				availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+				if assert.NotNil(t, availableCond) {
+					assert.Equal(t, metav1.ConditionFalse, availableCond.Status)
+					assert.Equal(t, addonsv1alpha1.AddonReasonCSVReplacing, availableCond.Reason)
+				}
+			})
+		}
+	})
+
+	t.Run("present CSV clears Orphaned", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithCatalogSourceImage()
+		meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+			Type:   addonsv1alpha1.Orphaned,
+			Status: metav1.ConditionTrue,
+			Reason: "CSVMissing",
+		})
+
+		c.On("Get", mock.Anything, csvKey,
+			mock.IsType(&operatorsv1alpha1.ClusterServiceVersion{})).
+			Run(func(args mock.Arguments) {
+				csv := args.Get(2).(*operatorsv1alpha1.ClusterServiceVersion)
+				csv.Status.Phase = operatorsv1alpha1.CSVPhaseSucceeded
+			}).
+			Return(nil)
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		requeue, err := r.observeCurrentCSV(ctx, addon, csvKey, csvKey)
+		require.NoError(t, err)
+		assert.False(t, requeue)
+
+		c.StatusMock.AssertCalled(t, "Update", mock.Anything, addon, mock.Anything)
+		orphanedCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Orphaned)
+		if assert.NotNil(t, orphanedCond) {
+			assert.Equal(t, metav1.ConditionFalse, orphanedCond.Status)
+			assert.Equal(t, "CSVRestored", orphanedCond.Reason)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+// Subscription conditions OLM reports deprecations from the catalog with.
+var olmDeprecationConditionTypes = []operatorsv1alpha1.SubscriptionConditionType{
+	"PackageDeprecated",
+	"ChannelDeprecated",
+	"BundleDeprecated",
+}
+
+// Reflects whether the catalog marks the package, channel or bundle
+// of the given Addon as deprecated in the Deprecated status condition.
+func (r *AddonReconciler) observeDeprecation(
+	ctx context.Context, addon *addonsv1alpha1.Addon) error {
+	subscription := &operatorsv1alpha1.Subscription{}
+	if err := r.Get(ctx, client.ObjectKey{
+		Name:      addon.Name,
+		Namespace: getCommonInstallOptions(addon).Namespace,
+	}, subscription); err != nil {
+		return fmt.Errorf("getting Subscription: %w", err)
+	}
+
+	if !setDeprecatedCondition(addon, subscription) {
+		return nil
+	}
+	return r.updateAddonStatus(ctx, addon)
+}
+
+// Sets the Deprecated condition according to the deprecation conditions of the given Subscription.
+func setDeprecatedCondition(
+	addon *addonsv1alpha1.Addon, subscription *operatorsv1alpha1.Subscription) (changed bool) {
+	var deprecations []string
+	for _, condType := range olmDeprecationConditionTypes {
+		subCond := subscription.Status.GetCondition(condType)
+		if subCond.Status == corev1.ConditionTrue {
+			deprecations = append(deprecations, subCond.Message)
+		}
+	}
+
+	cond := metav1.Condition{
+		Type:               addonsv1alpha1.Deprecated,
+		Status:             metav1.ConditionFalse,
+		Reason:             "NotDeprecated",
+		Message:            "Package, channel and bundle are not deprecated.",
+		ObservedGeneration: addon.Generation,
+	}
+	if len(deprecations) > 0 {
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "DeprecatedInCatalog"
+		cond.Message = strings.Join(deprecations, " ")
+	}
+
+	currentCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Deprecated)
+	if currentCond != nil &&
+		currentCond.Status == cond.Status &&
+		currentCond.Reason == cond.Reason &&
+		currentCond.Message == cond.Message {
+		return false
+	}
+	meta.SetStatusCondition(&addon.Status.Conditions, cond)
+	return true
+}
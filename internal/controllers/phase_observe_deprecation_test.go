@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestObserveDeprecation(t *testing.T) {
+	c := testutil.NewClient()
+	r := &AddonReconciler{
+		Client: c,
+		Scheme: newTestSchemeWithAddonsv1alpha1(),
+	}
+
+	addon := testutil.NewAddon()
+
+	subscription := newTestSubscriptionWithDeprecatedChannel()
+	subscription.Name = addon.Name
+	subscription.Namespace = "addon-1"
+	c.ExpectGet(subscription)
+	c.ExpectStatusUpdate()
+
+	ctx := context.Background()
+	err := r.observeDeprecation(ctx, addon)
+	require.NoError(t, err)
+	c.StatusMock.AssertCalled(t, "Update", mock.Anything, addon, mock.Anything)
+	assert.True(t, meta.IsStatusConditionTrue(addon.Status.Conditions, addonsv1alpha1.Deprecated))
+
+	// unchanged condition is not written again
+	err = r.observeDeprecation(ctx, addon)
+	require.NoError(t, err)
+	c.StatusMock.AssertNumberOfCalls(t, "Update", 1)
+}
+
+func TestSetDeprecatedCondition(t *testing.T) {
+	t.Run("deprecated channel", func(t *testing.T) {
+		addon := testutil.NewAddon()
+		changed := setDeprecatedCondition(addon, newTestSubscriptionWithDeprecatedChannel())
+		assert.True(t, changed)
+
+		cond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Deprecated)
+		if assert.NotNil(t, cond) {
+			assert.Equal(t, metav1.ConditionTrue, cond.Status)
+			assert.Equal(t, "DeprecatedInCatalog", cond.Reason)
+			assert.Equal(t, "channel alpha is no longer supported, use stable.", cond.Message)
+		}
+	})
+
+	t.Run("non-deprecated channel clears condition", func(t *testing.T) {
+		addon := testutil.NewAddon()
+		setDeprecatedCondition(addon, newTestSubscriptionWithDeprecatedChannel())
+
+		// channel got switched
+		changed := setDeprecatedCondition(addon, &operatorsv1alpha1.Subscription{})
+		assert.True(t, changed)
+
+		cond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Deprecated)
+		if assert.NotNil(t, cond) {
+			assert.Equal(t, metav1.ConditionFalse, cond.Status)
+			assert.Equal(t, "NotDeprecated", cond.Reason)
+		}
+	})
+}
+
+func newTestSubscriptionWithDeprecatedChannel() *operatorsv1alpha1.Subscription {
+	return &operatorsv1alpha1.Subscription{
+		Status: operatorsv1alpha1.SubscriptionStatus{
+			Conditions: []operatorsv1alpha1.SubscriptionCondition{
+				{
+					Type:    "ChannelDeprecated",
+					Status:  corev1.ConditionTrue,
+					Reason:  "Deprecated",
+					Message: "channel alpha is no longer supported, use stable.",
+				},
+				{
+					Type:   "PackageDeprecated",
+					Status: corev1.ConditionFalse,
+				},
+			},
+		},
+	}
+}
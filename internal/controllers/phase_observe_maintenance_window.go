@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+// Reflects the maintenance window of the given Addon in its status conditions.
+// returns the duration after which the Addon needs to be reconciled again
+// to pick up the start or end of the maintenance window
+func (r *AddonReconciler) observeMaintenanceWindow(
+	ctx context.Context, addon *addonsv1alpha1.Addon) (requeueAfter time.Duration, err error) {
+	changed, requeueAfter := setMaintenanceCondition(addon, time.Now())
+	if !changed {
+		return requeueAfter, nil
+	}
+	return requeueAfter, r.updateAddonStatus(ctx, addon)
+}
+
+// Sets or removes the Maintenance condition depending on the Addons maintenance window
+// in relation to the given point in time.
+func setMaintenanceCondition(addon *addonsv1alpha1.Addon, now time.Time) (
+	changed bool, requeueAfter time.Duration) {
+	window := addon.Spec.MaintenanceWindow
+	if window == nil || !now.Before(window.End.Time) {
+		// no window or window has ended
+		return removeMaintenanceCondition(addon), 0
+	}
+
+	if now.Before(window.Start.Time) {
+		// window is upcoming
+		return removeMaintenanceCondition(addon), window.Start.Sub(now)
+	}
+
+	message := "Addon is in a planned maintenance window until " +
+		window.End.UTC().Format(time.RFC3339)
+	currentCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Maintenance)
+	if currentCond == nil ||
+		currentCond.Status != metav1.ConditionTrue ||
+		currentCond.Message != message {
+		meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+			Type:               addonsv1alpha1.Maintenance,
+			Status:             metav1.ConditionTrue,
+			Reason:             "MaintenanceWindowActive",
+			Message:            message,
+			ObservedGeneration: addon.Generation,
+		})
+		changed = true
+	}
+	return changed, window.End.Sub(now)
+}
+
+func removeMaintenanceCondition(addon *addonsv1alpha1.Addon) (changed bool) {
+	if meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Maintenance) == nil {
+		return false
+	}
+	meta.RemoveStatusCondition(&addon.Status.Conditions, addonsv1alpha1.Maintenance)
+	return true
+}
+
+// Restores the last reported Available condition and Phase of the given Addon,
+// if its workloads or CSV are unready during an active maintenance window.
+// Planned maintenance commonly takes components down,
+// which must not flip Available and trigger alerting.
+// Only call this for unready workloads, terminating or misconfigured Addons are always reported.
+func (r *AddonReconciler) suppressUnavailableDuringMaintenance(
+	ctx context.Context, addon *addonsv1alpha1.Addon) error {
+	if !addon.DeletionTimestamp.IsZero() ||
+		!meta.IsStatusConditionTrue(addon.Status.Conditions, addonsv1alpha1.Maintenance) ||
+		!meta.IsStatusConditionFalse(addon.Status.Conditions, addonsv1alpha1.Available) {
+		return nil
+	}
+
+	// the cached Addon still holds the last reported status
+	reportedAddon := &addonsv1alpha1.Addon{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(addon), reportedAddon); err != nil {
+		return fmt.Errorf("getting reported Addon: %w", err)
+	}
+	reportedCond := meta.FindStatusCondition(
+		reportedAddon.Status.Conditions, addonsv1alpha1.Available)
+	if reportedCond == nil || reportedCond.Status != metav1.ConditionTrue {
+		// the Addon was not available before, nothing to suppress
+		return nil
+	}
+	meta.SetStatusCondition(&addon.Status.Conditions, *reportedCond)
+	addon.Status.Phase = addonsv1alpha1.PhaseReady
+	return nil
+}
@@ -0,0 +1,326 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestSetMaintenanceCondition(t *testing.T) {
+	now := time.Date(2021, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("window active", func(t *testing.T) {
+		addon := newTestAddonWithMaintenanceWindow(
+			now.Add(-time.Hour), now.Add(time.Hour))
+
+		changed, requeueAfter := setMaintenanceCondition(addon, now)
+		assert.True(t, changed)
+		assert.Equal(t, time.Hour, requeueAfter)
+
+		maintenanceCond := meta.FindStatusCondition(
+			addon.Status.Conditions, addonsv1alpha1.Maintenance)
+		if assert.NotNil(t, maintenanceCond) {
+			assert.Equal(t, metav1.ConditionTrue, maintenanceCond.Status)
+			assert.Equal(t, "MaintenanceWindowActive", maintenanceCond.Reason)
+		}
+
+		// second call is a no-op
+		changed, _ = setMaintenanceCondition(addon, now)
+		assert.False(t, changed)
+	})
+
+	t.Run("window upcoming", func(t *testing.T) {
+		addon := newTestAddonWithMaintenanceWindow(
+			now.Add(time.Hour), now.Add(2*time.Hour))
+
+		changed, requeueAfter := setMaintenanceCondition(addon, now)
+		assert.False(t, changed)
+		assert.Equal(t, time.Hour, requeueAfter)
+		assert.Nil(t, meta.FindStatusCondition(
+			addon.Status.Conditions, addonsv1alpha1.Maintenance))
+	})
+
+	t.Run("window ended", func(t *testing.T) {
+		addon := newTestAddonWithMaintenanceWindow(
+			now.Add(-2*time.Hour), now.Add(-time.Hour))
+		meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+			Type:   addonsv1alpha1.Maintenance,
+			Status: metav1.ConditionTrue,
+			Reason: "MaintenanceWindowActive",
+		})
+
+		changed, requeueAfter := setMaintenanceCondition(addon, now)
+		assert.True(t, changed)
+		assert.Equal(t, time.Duration(0), requeueAfter)
+		assert.Nil(t, meta.FindStatusCondition(
+			addon.Status.Conditions, addonsv1alpha1.Maintenance))
+	})
+
+	t.Run("window removed", func(t *testing.T) {
+		addon := newTestAddonWithoutNamespace()
+		meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+			Type:   addonsv1alpha1.Maintenance,
+			Status: metav1.ConditionTrue,
+			Reason: "MaintenanceWindowActive",
+		})
+
+		changed, _ := setMaintenanceCondition(addon, now)
+		assert.True(t, changed)
+		assert.Nil(t, meta.FindStatusCondition(
+			addon.Status.Conditions, addonsv1alpha1.Maintenance))
+	})
+}
+
+func TestObserveMaintenanceWindow(t *testing.T) {
+	t.Run("reports Maintenance condition", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		addon := newTestAddonWithMaintenanceWindow(
+			time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+
+		ctx := context.Background()
+		requeueAfter, err := r.observeMaintenanceWindow(ctx, addon)
+		require.NoError(t, err)
+		assert.NotZero(t, requeueAfter)
+		assert.True(t, meta.IsStatusConditionTrue(
+			addon.Status.Conditions, addonsv1alpha1.Maintenance))
+		c.StatusMock.AssertCalled(t, "Update", mock.Anything, addon, mock.Anything)
+	})
+
+	t.Run("no window", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		ctx := context.Background()
+		requeueAfter, err := r.observeMaintenanceWindow(ctx, newTestAddonWithoutNamespace())
+		require.NoError(t, err)
+		assert.Zero(t, requeueAfter)
+		c.StatusMock.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestSuppressUnavailableDuringMaintenance(t *testing.T) {
+	newTestAvailableAddon := func() *addonsv1alpha1.Addon {
+		addon := newTestAddonWithRequiredDeployments()
+		addon.Spec.MaintenanceWindow = &addonsv1alpha1.AddonMaintenanceWindow{
+			Start: metav1.NewTime(time.Now().Add(-time.Hour)),
+			End:   metav1.NewTime(time.Now().Add(time.Hour)),
+		}
+		meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+			Type:   addonsv1alpha1.Available,
+			Status: metav1.ConditionTrue,
+			Reason: "FullyReconciled",
+		})
+		addon.Status.Phase = addonsv1alpha1.PhaseReady
+		return addon
+	}
+	mockUnreadyDeployment := func(c *testutil.Client) {
+		c.On("Get", mock.Anything, mock.Anything, mock.IsType(&appsv1.Deployment{})).
+			Return(newTestErrNotFound())
+	}
+
+	t.Run("unready Deployments inside window keep Available", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAvailableAddon()
+		reportedAddon := addon.DeepCopy()
+		setMaintenanceCondition(addon, time.Now())
+
+		mockUnreadyDeployment(c)
+		c.On("Get", mock.Anything, client.ObjectKey{Name: addon.Name},
+			mock.IsType(&addonsv1alpha1.Addon{})).
+			Run(func(args mock.Arguments) {
+				reportedAddon.DeepCopyInto(args.Get(2).(*addonsv1alpha1.Addon))
+			}).
+			Return(nil)
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		requeue, err := r.observeRequiredDeployments(ctx, addon)
+		require.NoError(t, err)
+		assert.True(t, requeue)
+
+		availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+		if assert.NotNil(t, availableCond) {
+			assert.Equal(t, metav1.ConditionTrue, availableCond.Status)
+			assert.Equal(t, "FullyReconciled", availableCond.Reason)
+		}
+		assert.Equal(t, addonsv1alpha1.PhaseReady, addon.Status.Phase)
+		assert.True(t, meta.IsStatusConditionTrue(
+			addon.Status.Conditions, addonsv1alpha1.Maintenance))
+	})
+
+	t.Run("unready Deployments outside window flip Available", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAvailableAddon()
+		addon.Spec.MaintenanceWindow = nil
+
+		mockUnreadyDeployment(c)
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		_, err := r.observeRequiredDeployments(ctx, addon)
+		require.NoError(t, err)
+
+		c.AssertNotCalled(t, "Get", mock.Anything, mock.Anything,
+			mock.IsType(&addonsv1alpha1.Addon{}))
+		assert.True(t, meta.IsStatusConditionFalse(
+			addon.Status.Conditions, addonsv1alpha1.Available))
+		assert.Equal(t, addonsv1alpha1.PhasePending, addon.Status.Phase)
+	})
+
+	t.Run("unavailable before window stays unavailable", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithMaintenanceWindow(
+			time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+		setMaintenanceCondition(addon, time.Now())
+
+		c.On("Get", mock.Anything, client.ObjectKey{Name: addon.Name},
+			mock.IsType(&addonsv1alpha1.Addon{})).
+			Return(nil)
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.reportUnreadyCSV(ctx, addon, "UnreadyCSV", "pending")
+		require.NoError(t, err)
+
+		availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+		if assert.NotNil(t, availableCond) {
+			assert.Equal(t, metav1.ConditionFalse, availableCond.Status)
+			assert.Equal(t, "UnreadyCSV", availableCond.Reason)
+		}
+		assert.Equal(t, addonsv1alpha1.PhasePending, addon.Status.Phase)
+	})
+
+	t.Run("configuration error inside window flips Available", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAvailableAddon()
+		setMaintenanceCondition(addon, time.Now())
+
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.reportConfigurationError(ctx, addon, "broken")
+		require.NoError(t, err)
+
+		availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+		if assert.NotNil(t, availableCond) {
+			assert.Equal(t, metav1.ConditionFalse, availableCond.Status)
+			assert.Equal(t, "ConfigurationError", availableCond.Reason)
+		}
+		assert.Equal(t, addonsv1alpha1.PhaseError, addon.Status.Phase)
+	})
+
+	t.Run("termination inside window flips Available", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAvailableAddon()
+		setMaintenanceCondition(addon, time.Now())
+		now := metav1.Now()
+		addon.DeletionTimestamp = &now
+
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.reportTerminationStatus(ctx, addon)
+		require.NoError(t, err)
+
+		availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+		if assert.NotNil(t, availableCond) {
+			assert.Equal(t, metav1.ConditionFalse, availableCond.Status)
+			assert.Equal(t, "Terminating", availableCond.Reason)
+		}
+		assert.Equal(t, addonsv1alpha1.PhaseTerminating, addon.Status.Phase)
+	})
+
+	t.Run("deleting Addon with unready Deployments flips Available", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAvailableAddon()
+		setMaintenanceCondition(addon, time.Now())
+		now := metav1.Now()
+		addon.DeletionTimestamp = &now
+
+		mockUnreadyDeployment(c)
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		_, err := r.observeRequiredDeployments(ctx, addon)
+		require.NoError(t, err)
+
+		c.AssertNotCalled(t, "Get", mock.Anything, mock.Anything,
+			mock.IsType(&addonsv1alpha1.Addon{}))
+		assert.True(t, meta.IsStatusConditionFalse(
+			addon.Status.Conditions, addonsv1alpha1.Available))
+	})
+}
+
+func newTestAddonWithMaintenanceWindow(start, end time.Time) *addonsv1alpha1.Addon {
+	addon := newTestAddonWithoutNamespace()
+	addon.Spec.MaintenanceWindow = &addonsv1alpha1.AddonMaintenanceWindow{
+		Start: metav1.NewTime(start),
+		End:   metav1.NewTime(end),
+	}
+	return addon
+}
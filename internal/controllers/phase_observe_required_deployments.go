@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+// Checks that all Deployments required by the given Addon are fully available
+// returns a bool that signals the caller to stop reconciliation and retry later
+func (r *AddonReconciler) observeRequiredDeployments(
+	ctx context.Context, addon *addonsv1alpha1.Addon) (requeue bool, err error) {
+	targetNamespace := getCommonInstallOptions(addon).Namespace
+
+	var unreadyDeployments []string
+	for _, name := range addon.Spec.RequiredDeployments {
+		deployment := &appsv1.Deployment{}
+		err := r.apiReader().Get(ctx, client.ObjectKey{
+			Name:      name,
+			Namespace: targetNamespace,
+		}, deployment)
+		if k8sApiErrors.IsNotFound(err) {
+			// not yet created by the operator of the Addon
+			unreadyDeployments = append(unreadyDeployments, name)
+			continue
+		}
+		if err != nil {
+			return false, fmt.Errorf("getting required Deployment: %w", err)
+		}
+
+		if !isDeploymentFullyAvailable(deployment) {
+			unreadyDeployments = append(unreadyDeployments, name)
+		}
+	}
+
+	if len(unreadyDeployments) > 0 {
+		meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+			Type:   addonsv1alpha1.Available,
+			Status: metav1.ConditionFalse,
+			Reason: addonsv1alpha1.AddonReasonWorkloadsNotReady,
+			Message: fmt.Sprintf(
+				"Deployments not yet fully available: %s",
+				strings.Join(unreadyDeployments, ", ")),
+			ObservedGeneration: addon.Generation,
+		})
+		addon.Status.ObservedGeneration = addon.Generation
+		addon.Status.Phase = addonsv1alpha1.PhasePending
+		if err := r.suppressUnavailableDuringMaintenance(ctx, addon); err != nil {
+			return false, err
+		}
+		return true, r.updateAddonStatus(ctx, addon)
+	}
+
+	return false, nil
+}
+
+// A Deployment is fully available when its latest spec is rolled out
+// and all desired replicas are updated and available.
+func isDeploymentFullyAvailable(deployment *appsv1.Deployment) bool {
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return false
+	}
+
+	// replicas defaults to 1
+	var replicas int32 = 1
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+
+	return deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas
+}
@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestObserveRequiredDeployments(t *testing.T) {
+	t.Run("unready Deployment gates Available", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithRequiredDeployments()
+
+		c.On("Get", mock.Anything, client.ObjectKey{
+			Name:      "addon-operator",
+			Namespace: "addon-1",
+		}, mock.IsType(&appsv1.Deployment{})).
+			Run(func(args mock.Arguments) {
+				newTestDeployment(3, 1).DeepCopyInto(args.Get(2).(*appsv1.Deployment))
+			}).
+			Return(nil)
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		requeue, err := r.observeRequiredDeployments(ctx, addon)
+		require.NoError(t, err)
+		assert.True(t, requeue)
+
+		c.StatusMock.AssertCalled(t, "Update", mock.Anything, addon, mock.Anything)
+		availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+		if assert.NotNil(t, availableCond) {
+			assert.Equal(t, metav1.ConditionFalse, availableCond.Status)
+			assert.Equal(t, addonsv1alpha1.AddonReasonWorkloadsNotReady, availableCond.Reason)
+			assert.Contains(t, availableCond.Message, "addon-operator")
+		}
+	})
+
+	t.Run("missing Deployment gates Available", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithRequiredDeployments()
+
+		c.On("Get", mock.Anything, mock.Anything, mock.IsType(&appsv1.Deployment{})).
+			Return(newTestErrNotFound())
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		requeue, err := r.observeRequiredDeployments(ctx, addon)
+		require.NoError(t, err)
+		assert.True(t, requeue)
+
+		availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+		if assert.NotNil(t, availableCond) {
+			assert.Equal(t, addonsv1alpha1.AddonReasonWorkloadsNotReady, availableCond.Reason)
+		}
+	})
+
+	t.Run("ready Deployment allows Available", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithRequiredDeployments()
+
+		c.On("Get", mock.Anything, mock.Anything, mock.IsType(&appsv1.Deployment{})).
+			Run(func(args mock.Arguments) {
+				newTestDeployment(3, 3).DeepCopyInto(args.Get(2).(*appsv1.Deployment))
+			}).
+			Return(nil)
+
+		ctx := context.Background()
+		requeue, err := r.observeRequiredDeployments(ctx, addon)
+		require.NoError(t, err)
+		assert.False(t, requeue)
+
+		c.StatusMock.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestIsDeploymentFullyAvailable(t *testing.T) {
+	assert.True(t, isDeploymentFullyAvailable(newTestDeployment(3, 3)))
+	assert.False(t, isDeploymentFullyAvailable(newTestDeployment(3, 2)))
+
+	rollingOut := newTestDeployment(3, 3)
+	rollingOut.Generation = 2
+	assert.False(t, isDeploymentFullyAvailable(rollingOut))
+
+	defaultReplicas := newTestDeployment(1, 1)
+	defaultReplicas.Spec.Replicas = nil
+	assert.True(t, isDeploymentFullyAvailable(defaultReplicas))
+}
+
+func newTestAddonWithRequiredDeployments() *addonsv1alpha1.Addon {
+	addon := newTestAddonWithCatalogSourceImage()
+	addon.Spec.RequiredDeployments = []string{"addon-operator"}
+	return addon
+}
+
+func newTestDeployment(replicas, available int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "addon-operator",
+			Namespace:  "addon-1",
+			Generation: 1,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+		},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 1,
+			UpdatedReplicas:    replicas,
+			AvailableReplicas:  available,
+		},
+	}
+}
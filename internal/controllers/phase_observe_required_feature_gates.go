@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+// The OpenShift config API is not vendored,
+// so the cluster FeatureGate is handled as an unstructured object.
+var featureGateGVK = schema.GroupVersionKind{
+	Group:   "config.openshift.io",
+	Version: "v1",
+	Kind:    "FeatureGate",
+}
+
+// Name of the cluster wide FeatureGate singleton.
+const clusterFeatureGateName = "cluster"
+
+var clusterVersionGVK = schema.GroupVersionKind{
+	Group:   "config.openshift.io",
+	Version: "v1",
+	Kind:    "ClusterVersion",
+}
+
+// Name of the cluster wide ClusterVersion singleton.
+const clusterVersionName = "version"
+
+// Checks that all feature gates required by the given Addon are enabled on the cluster
+// returns a bool that signals the caller to stop reconciliation and retry later
+func (r *AddonReconciler) observeRequiredFeatureGates(
+	ctx context.Context, addon *addonsv1alpha1.Addon) (requeue bool, err error) {
+	if len(addon.Spec.RequiredFeatureGates) == 0 {
+		return false, nil
+	}
+
+	enabledFeatureGates, err := r.getEnabledFeatureGates(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var missingFeatureGates []string
+	for _, name := range addon.Spec.RequiredFeatureGates {
+		if _, ok := enabledFeatureGates[name]; !ok {
+			missingFeatureGates = append(missingFeatureGates, name)
+		}
+	}
+
+	if len(missingFeatureGates) > 0 {
+		meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+			Type:   addonsv1alpha1.Available,
+			Status: metav1.ConditionFalse,
+			Reason: addonsv1alpha1.AddonReasonRequirementsNotMet,
+			Message: fmt.Sprintf(
+				"Required feature gates are not enabled: %s",
+				strings.Join(missingFeatureGates, ", ")),
+			ObservedGeneration: addon.Generation,
+		})
+		addon.Status.ObservedGeneration = addon.Generation
+		addon.Status.Phase = addonsv1alpha1.PhasePending
+		return true, r.updateAddonStatus(ctx, addon)
+	}
+
+	return false, nil
+}
+
+// Returns the names of all feature gates enabled on the cluster.
+// Clusters without a FeatureGate object have no feature gates enabled.
+func (r *AddonReconciler) getEnabledFeatureGates(ctx context.Context) (
+	map[string]struct{}, error) {
+	featureGate := &unstructured.Unstructured{}
+	featureGate.SetGroupVersionKind(featureGateGVK)
+	err := r.Get(ctx, client.ObjectKey{Name: clusterFeatureGateName}, featureGate)
+	if meta.IsNoMatchError(err) || k8sApiErrors.IsNotFound(err) {
+		// not an OpenShift cluster
+		return map[string]struct{}{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting cluster FeatureGate: %w", err)
+	}
+
+	clusterVersion, err := r.getClusterVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return enabledFeatureGates(featureGate, clusterVersion), nil
+}
+
+// Returns the version the cluster is currently running or upgrading to.
+// ClusterVersion is not watched, so it is read through the APIReader.
+func (r *AddonReconciler) getClusterVersion(ctx context.Context) (string, error) {
+	clusterVersion := &unstructured.Unstructured{}
+	clusterVersion.SetGroupVersionKind(clusterVersionGVK)
+	err := r.apiReader().Get(ctx, client.ObjectKey{Name: clusterVersionName}, clusterVersion)
+	if meta.IsNoMatchError(err) || k8sApiErrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("getting ClusterVersion: %w", err)
+	}
+
+	version, _, _ := unstructured.NestedString(
+		clusterVersion.Object, "status", "desired", "version")
+	return version, nil
+}
+
+// Collects the feature gates enabled for the given cluster version.
+// The status of the FeatureGate reports enabled and disabled feature gates per version,
+// only when it has no entry for the cluster version, the gates explicitly enabled
+// via the CustomNoUpgrade feature set are used.
+// The name of the feature set itself is included, so Addons can require e.g. TechPreviewNoUpgrade.
+func enabledFeatureGates(
+	featureGate *unstructured.Unstructured, clusterVersion string) map[string]struct{} {
+	enabled := map[string]struct{}{}
+
+	// .spec.featureSet
+	if featureSet, _, _ := unstructured.NestedString(
+		featureGate.Object, "spec", "featureSet"); len(featureSet) > 0 {
+		enabled[featureSet] = struct{}{}
+	}
+
+	// .status.featureGates[].enabled[].name
+	if versionedMap, ok := featureGatesForVersion(featureGate, clusterVersion); ok {
+		for _, name := range featureGateNames(versionedMap, "enabled") {
+			enabled[name] = struct{}{}
+		}
+		for _, name := range featureGateNames(versionedMap, "disabled") {
+			delete(enabled, name)
+		}
+		return enabled
+	}
+
+	// .spec.customNoUpgrade.enabled[]
+	customEnabled, _, _ := unstructured.NestedStringSlice(
+		featureGate.Object, "spec", "customNoUpgrade", "enabled")
+	for _, name := range customEnabled {
+		enabled[name] = struct{}{}
+	}
+	customDisabled, _, _ := unstructured.NestedStringSlice(
+		featureGate.Object, "spec", "customNoUpgrade", "disabled")
+	for _, name := range customDisabled {
+		delete(enabled, name)
+	}
+
+	return enabled
+}
+
+// Returns the .status.featureGates[] entry of the given FeatureGate for the given version.
+func featureGatesForVersion(
+	featureGate *unstructured.Unstructured, version string) (map[string]interface{}, bool) {
+	if len(version) == 0 {
+		return nil, false
+	}
+
+	statusFeatureGates, _, _ := unstructured.NestedSlice(
+		featureGate.Object, "status", "featureGates")
+	for _, versioned := range statusFeatureGates {
+		versionedMap, ok := versioned.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if v, _, _ := unstructured.NestedString(versionedMap, "version"); v == version {
+			return versionedMap, true
+		}
+	}
+	return nil, false
+}
+
+// Returns the names of the feature gates listed under the given field,
+// e.g. enabled[].name.
+func featureGateNames(versionedMap map[string]interface{}, field string) []string {
+	gates, _, _ := unstructured.NestedSlice(versionedMap, field)
+
+	var names []string
+	for _, gate := range gates {
+		gateMap, ok := gate.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, _, _ := unstructured.NestedString(gateMap, "name"); len(name) > 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,225 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestObserveRequiredFeatureGates(t *testing.T) {
+	t.Run("met requirements", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithCatalogSourceImage()
+		addon.Spec.RequiredFeatureGates = []string{"CSIDriverSharedResource"}
+
+		mockGetClusterFeatureGate(c, newTestFeatureGate("CSIDriverSharedResource"))
+		mockGetClusterVersion(c, testClusterVersion)
+
+		ctx := context.Background()
+		requeue, err := r.observeRequiredFeatureGates(ctx, addon)
+		require.NoError(t, err)
+		assert.False(t, requeue)
+		c.StatusMock.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unmet requirements", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithCatalogSourceImage()
+		addon.Spec.RequiredFeatureGates = []string{"CSIDriverSharedResource", "BuildCSIVolumes"}
+
+		mockGetClusterFeatureGate(c, newTestFeatureGate("CSIDriverSharedResource"))
+		mockGetClusterVersion(c, testClusterVersion)
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		requeue, err := r.observeRequiredFeatureGates(ctx, addon)
+		require.NoError(t, err)
+		assert.True(t, requeue)
+
+		c.StatusMock.AssertCalled(t, "Update", mock.Anything, addon, mock.Anything)
+		availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+		if assert.NotNil(t, availableCond) {
+			assert.Equal(t, metav1.ConditionFalse, availableCond.Status)
+			assert.Equal(t, addonsv1alpha1.AddonReasonRequirementsNotMet, availableCond.Reason)
+			assert.Contains(t, availableCond.Message, "BuildCSIVolumes")
+			assert.NotContains(t, availableCond.Message, "CSIDriverSharedResource")
+		}
+		assert.Equal(t, addonsv1alpha1.PhasePending, addon.Status.Phase)
+	})
+
+	t.Run("no FeatureGate on the cluster", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithCatalogSourceImage()
+		addon.Spec.RequiredFeatureGates = []string{"CSIDriverSharedResource"}
+
+		c.On("Get", mock.Anything, client.ObjectKey{Name: clusterFeatureGateName},
+			mock.IsType(&unstructured.Unstructured{})).
+			Return(newTestErrNotFound())
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		requeue, err := r.observeRequiredFeatureGates(ctx, addon)
+		require.NoError(t, err)
+		assert.True(t, requeue)
+		assert.False(t, meta.IsStatusConditionTrue(addon.Status.Conditions, addonsv1alpha1.Available))
+	})
+
+	t.Run("no requirements", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		ctx := context.Background()
+		requeue, err := r.observeRequiredFeatureGates(ctx, newTestAddonWithCatalogSourceImage())
+		require.NoError(t, err)
+		assert.False(t, requeue)
+		c.AssertNotCalled(t, "Get", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestEnabledFeatureGates(t *testing.T) {
+	t.Run("status feature gates of the cluster version", func(t *testing.T) {
+		featureGate := newTestFeatureGate()
+		require.NoError(t, unstructured.SetNestedSlice(featureGate.Object, []interface{}{
+			newTestFeatureGateStatus("4.9.0", []string{"BuildCSIVolumes"}, nil),
+			newTestFeatureGateStatus(testClusterVersion, []string{"CSIDriverSharedResource"}, nil),
+		}, "status", "featureGates"))
+
+		assert.Equal(t, map[string]struct{}{
+			"CSIDriverSharedResource": {},
+		}, enabledFeatureGates(featureGate, testClusterVersion))
+	})
+
+	t.Run("disabled feature gates", func(t *testing.T) {
+		featureGate := newTestFeatureGate()
+		require.NoError(t, unstructured.SetNestedSlice(featureGate.Object, []interface{}{
+			newTestFeatureGateStatus(testClusterVersion,
+				[]string{"CSIDriverSharedResource"}, []string{"BuildCSIVolumes"}),
+		}, "status", "featureGates"))
+		// the status is authoritative over the spec
+		require.NoError(t, unstructured.SetNestedStringSlice(featureGate.Object,
+			[]string{"BuildCSIVolumes"}, "spec", "customNoUpgrade", "enabled"))
+
+		assert.Equal(t, map[string]struct{}{
+			"CSIDriverSharedResource": {},
+		}, enabledFeatureGates(featureGate, testClusterVersion))
+	})
+
+	t.Run("custom feature gates without status", func(t *testing.T) {
+		featureGate := newTestFeatureGate()
+		unstructured.RemoveNestedField(featureGate.Object, "status")
+		require.NoError(t, unstructured.SetNestedField(featureGate.Object,
+			"CustomNoUpgrade", "spec", "featureSet"))
+		require.NoError(t, unstructured.SetNestedStringSlice(featureGate.Object,
+			[]string{"BuildCSIVolumes", "CSIDriverSharedResource"}, "spec", "customNoUpgrade", "enabled"))
+		require.NoError(t, unstructured.SetNestedStringSlice(featureGate.Object,
+			[]string{"CSIDriverSharedResource"}, "spec", "customNoUpgrade", "disabled"))
+
+		assert.Equal(t, map[string]struct{}{
+			"CustomNoUpgrade": {},
+			"BuildCSIVolumes": {},
+		}, enabledFeatureGates(featureGate, testClusterVersion))
+	})
+
+	t.Run("feature set", func(t *testing.T) {
+		featureGate := newTestFeatureGate("CSIDriverSharedResource")
+		require.NoError(t, unstructured.SetNestedField(featureGate.Object,
+			"TechPreviewNoUpgrade", "spec", "featureSet"))
+
+		assert.Equal(t, map[string]struct{}{
+			"TechPreviewNoUpgrade":    {},
+			"CSIDriverSharedResource": {},
+		}, enabledFeatureGates(featureGate, testClusterVersion))
+	})
+}
+
+func mockGetClusterFeatureGate(c *testutil.Client, featureGate *unstructured.Unstructured) {
+	c.On("Get", mock.Anything, client.ObjectKey{Name: clusterFeatureGateName},
+		mock.IsType(&unstructured.Unstructured{})).
+		Run(func(args mock.Arguments) {
+			obj := args.Get(2).(*unstructured.Unstructured)
+			featureGate.DeepCopyInto(obj)
+		}).
+		Return(nil)
+}
+
+// Version of the cluster the test FeatureGate reports feature gates for.
+const testClusterVersion = "4.10.0"
+
+func mockGetClusterVersion(c *testutil.Client, version string) {
+	c.On("Get", mock.Anything, client.ObjectKey{Name: clusterVersionName},
+		mock.IsType(&unstructured.Unstructured{})).
+		Run(func(args mock.Arguments) {
+			obj := args.Get(2).(*unstructured.Unstructured)
+			obj.Object = map[string]interface{}{
+				"status": map[string]interface{}{
+					"desired": map[string]interface{}{
+						"version": version,
+					},
+				},
+			}
+		}).
+		Return(nil)
+}
+
+func newTestFeatureGate(enabled ...string) *unstructured.Unstructured {
+	featureGate := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"status": map[string]interface{}{
+				"featureGates": []interface{}{
+					newTestFeatureGateStatus(testClusterVersion, enabled, nil),
+				},
+			},
+		},
+	}
+	featureGate.SetGroupVersionKind(featureGateGVK)
+	featureGate.SetName(clusterFeatureGateName)
+	return featureGate
+}
+
+func newTestFeatureGateStatus(version string, enabled, disabled []string) interface{} {
+	gates := func(names []string) []interface{} {
+		list := make([]interface{}, len(names))
+		for i, name := range names {
+			list[i] = map[string]interface{}{"name": name}
+		}
+		return list
+	}
+
+	return map[string]interface{}{
+		"version":  version,
+		"enabled":  gates(enabled),
+		"disabled": gates(disabled),
+	}
+}
@@ -0,0 +1,157 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blang/semver/v4"
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+// PackageManifests are served by the OLM package server for every package in a catalog.
+var packageManifestGVK = schema.GroupVersionKind{
+	Group:   "packages.operators.coreos.com",
+	Version: "v1",
+	Kind:    "PackageManifest",
+}
+
+// Reflects whether a newer version of the given Addon is available in its channel
+// in the UpgradeAvailable status condition.
+func (r *AddonReconciler) observeUpgradeAvailable(
+	ctx context.Context, addon *addonsv1alpha1.Addon) error {
+	installedVersion, err := r.getInstalledVersion(ctx, addon)
+	if err != nil {
+		return err
+	}
+
+	channelHeadVersion, err := r.getChannelHeadVersion(ctx, addon)
+	if err != nil {
+		return err
+	}
+
+	if !setUpgradeAvailableCondition(addon, installedVersion, channelHeadVersion) {
+		return nil
+	}
+	return r.updateAddonStatus(ctx, addon)
+}
+
+// Returns the version of the CSV installed for the given Addon,
+// returns nil, if no CSV is installed (yet).
+func (r *AddonReconciler) getInstalledVersion(
+	ctx context.Context, addon *addonsv1alpha1.Addon) (*semver.Version, error) {
+	targetNamespace := getCommonInstallOptions(addon).Namespace
+
+	subscription := &operatorsv1alpha1.Subscription{}
+	if err := r.Get(ctx, client.ObjectKey{
+		Name:      addon.Name,
+		Namespace: targetNamespace,
+	}, subscription); err != nil {
+		return nil, fmt.Errorf("getting Subscription: %w", err)
+	}
+	if len(subscription.Status.InstalledCSV) == 0 {
+		return nil, nil
+	}
+
+	csv := &operatorsv1alpha1.ClusterServiceVersion{}
+	err := r.Get(ctx, client.ObjectKey{
+		Name:      subscription.Status.InstalledCSV,
+		Namespace: targetNamespace,
+	}, csv)
+	if k8sApiErrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting installed CSV: %w", err)
+	}
+	return &csv.Spec.Version.Version, nil
+}
+
+// Returns the version of the latest CSV in the channel of the given Addon,
+// as served from the CatalogSource of the Addon.
+// returns nil, if the package is not (yet) served or has no valid version.
+func (r *AddonReconciler) getChannelHeadVersion(
+	ctx context.Context, addon *addonsv1alpha1.Addon) (*semver.Version, error) {
+	commonConfig := getCommonInstallOptions(addon)
+
+	// PackageManifests are not watched, so they are read from the API server directly.
+	packageManifest := &unstructured.Unstructured{}
+	packageManifest.SetGroupVersionKind(packageManifestGVK)
+	err := r.apiReader().Get(ctx, client.ObjectKey{
+		Name:      commonConfig.PackageName,
+		Namespace: commonConfig.Namespace,
+	}, packageManifest)
+	if k8sApiErrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting PackageManifest: %w", err)
+	}
+
+	// the same package may be served from another catalog
+	if catalogSource, _, _ := unstructured.NestedString(
+		packageManifest.Object, "status", "catalogSource"); catalogSource != addon.Name {
+		return nil, nil
+	}
+
+	channels, _, _ := unstructured.NestedSlice(packageManifest.Object, "status", "channels")
+	for _, channel := range channels {
+		channelObj, ok := channel.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, _, _ := unstructured.NestedString(channelObj, "name"); name != commonConfig.Channel {
+			continue
+		}
+		rawVersion, _, _ := unstructured.NestedString(channelObj, "currentCSVDesc", "version")
+		version, err := semver.ParseTolerant(rawVersion)
+		if err != nil {
+			return nil, nil
+		}
+		return &version, nil
+	}
+	return nil, nil
+}
+
+// Sets the UpgradeAvailable condition by comparing the installed version with the
+// version of the channel head.
+// The CurrentCSV of a Subscription only moves to a newer CSV once OLM resolved the upgrade,
+// so the channel head is taken from the catalog instead.
+// CSV names don't have to follow their version, so versions are compared.
+func setUpgradeAvailableCondition(
+	addon *addonsv1alpha1.Addon, installedVersion, channelHeadVersion *semver.Version) (changed bool) {
+	cond := metav1.Condition{
+		Type:               addonsv1alpha1.UpgradeAvailable,
+		Status:             metav1.ConditionFalse,
+		Reason:             "AtLatestKnownVersion",
+		Message:            "No newer version is known",
+		ObservedGeneration: addon.Generation,
+	}
+	if installedVersion != nil {
+		cond.Message = fmt.Sprintf("Installed version %s is the latest known version", installedVersion)
+	}
+	if installedVersion != nil && channelHeadVersion != nil &&
+		channelHeadVersion.GT(*installedVersion) {
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "NewerVersionAvailable"
+		cond.Message = fmt.Sprintf("Version %s is available, installed version is %s",
+			channelHeadVersion, installedVersion)
+	}
+
+	currentCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.UpgradeAvailable)
+	if currentCond != nil &&
+		currentCond.Status == cond.Status &&
+		currentCond.Reason == cond.Reason &&
+		currentCond.Message == cond.Message {
+		return false
+	}
+	meta.SetStatusCondition(&addon.Status.Conditions, cond)
+	return true
+}
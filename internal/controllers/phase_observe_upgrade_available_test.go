@@ -0,0 +1,245 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	opversion "github.com/operator-framework/api/pkg/lib/version"
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestObserveUpgradeAvailable(t *testing.T) {
+	t.Run("newer version in channel not installed", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithUpgradeChannel()
+
+		// OLM has not resolved the upgrade, e.g. because it waits for approval
+		c.On("Get", mock.Anything, client.ObjectKey{
+			Name:      addon.Name,
+			Namespace: "addon-1",
+		}, mock.IsType(&operatorsv1alpha1.Subscription{})).
+			Run(func(args mock.Arguments) {
+				sub := args.Get(2).(*operatorsv1alpha1.Subscription)
+				sub.Status.InstalledCSV = "addon-1.v1.0.0"
+				sub.Status.CurrentCSV = "addon-1.v1.0.0"
+			}).
+			Return(nil)
+		mockGetInstalledCSVVersion(c, "addon-1.v1.0.0", "1.0.0")
+		c.On("Get", mock.Anything, client.ObjectKey{
+			Name:      "addon-1-package",
+			Namespace: "addon-1",
+		}, mock.IsType(&unstructured.Unstructured{})).
+			Run(func(args mock.Arguments) {
+				obj := args.Get(2).(*unstructured.Unstructured)
+				obj.Object["status"] = newTestPackageManifestStatus(addon.Name, "addon-1.v1.1.0", "1.1.0")
+			}).
+			Return(nil)
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.observeUpgradeAvailable(ctx, addon)
+		require.NoError(t, err)
+		c.StatusMock.AssertCalled(t, "Update", mock.Anything, addon, mock.Anything)
+
+		cond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.UpgradeAvailable)
+		if assert.NotNil(t, cond) {
+			assert.Equal(t, metav1.ConditionTrue, cond.Status)
+			assert.Equal(t, "NewerVersionAvailable", cond.Reason)
+			assert.Equal(t, "Version 1.1.0 is available, installed version is 1.0.0", cond.Message)
+		}
+
+		// unchanged condition is not written again
+		err = r.observeUpgradeAvailable(ctx, addon)
+		require.NoError(t, err)
+		c.StatusMock.AssertNumberOfCalls(t, "Update", 1)
+	})
+
+	t.Run("renamed channel head of the installed version", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithUpgradeChannel()
+
+		c.On("Get", mock.Anything, mock.Anything, mock.IsType(&operatorsv1alpha1.Subscription{})).
+			Run(func(args mock.Arguments) {
+				sub := args.Get(2).(*operatorsv1alpha1.Subscription)
+				sub.Status.InstalledCSV = "addon-1.v1.0.0"
+			}).
+			Return(nil)
+		mockGetInstalledCSVVersion(c, "addon-1.v1.0.0", "1.0.0")
+		c.On("Get", mock.Anything, mock.Anything, mock.IsType(&unstructured.Unstructured{})).
+			Run(func(args mock.Arguments) {
+				obj := args.Get(2).(*unstructured.Unstructured)
+				obj.Object["status"] = newTestPackageManifestStatus(addon.Name, "addon-one.v1.0.0", "1.0.0")
+			}).
+			Return(nil)
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.observeUpgradeAvailable(ctx, addon)
+		require.NoError(t, err)
+		assert.False(t, meta.IsStatusConditionTrue(addon.Status.Conditions, addonsv1alpha1.UpgradeAvailable))
+	})
+
+	t.Run("package served from another catalog", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithUpgradeChannel()
+
+		c.On("Get", mock.Anything, mock.Anything, mock.IsType(&operatorsv1alpha1.Subscription{})).
+			Run(func(args mock.Arguments) {
+				sub := args.Get(2).(*operatorsv1alpha1.Subscription)
+				sub.Status.InstalledCSV = "addon-1.v1.0.0"
+			}).
+			Return(nil)
+		mockGetInstalledCSVVersion(c, "addon-1.v1.0.0", "1.0.0")
+		c.On("Get", mock.Anything, mock.Anything, mock.IsType(&unstructured.Unstructured{})).
+			Run(func(args mock.Arguments) {
+				obj := args.Get(2).(*unstructured.Unstructured)
+				obj.Object["status"] = newTestPackageManifestStatus("other-catalog", "addon-1.v2.0.0", "2.0.0")
+			}).
+			Return(nil)
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.observeUpgradeAvailable(ctx, addon)
+		require.NoError(t, err)
+		assert.False(t, meta.IsStatusConditionTrue(addon.Status.Conditions, addonsv1alpha1.UpgradeAvailable))
+	})
+
+	t.Run("package not served yet", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		addon := newTestAddonWithUpgradeChannel()
+
+		c.On("Get", mock.Anything, mock.Anything, mock.IsType(&operatorsv1alpha1.Subscription{})).
+			Return(nil)
+		c.On("Get", mock.Anything, mock.Anything, mock.IsType(&unstructured.Unstructured{})).
+			Return(newTestErrNotFound())
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		ctx := context.Background()
+		err := r.observeUpgradeAvailable(ctx, addon)
+		require.NoError(t, err)
+		assert.False(t, meta.IsStatusConditionTrue(addon.Status.Conditions, addonsv1alpha1.UpgradeAvailable))
+	})
+}
+
+func TestSetUpgradeAvailableCondition(t *testing.T) {
+	v1_0_0 := semver.MustParse("1.0.0")
+	v1_1_0 := semver.MustParse("1.1.0")
+
+	t.Run("newer version available", func(t *testing.T) {
+		addon := newTestAddonWithCatalogSourceImage()
+		changed := setUpgradeAvailableCondition(addon, &v1_0_0, &v1_1_0)
+		assert.True(t, changed)
+
+		cond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.UpgradeAvailable)
+		if assert.NotNil(t, cond) {
+			assert.Equal(t, metav1.ConditionTrue, cond.Status)
+			assert.Equal(t, "NewerVersionAvailable", cond.Reason)
+			assert.Contains(t, cond.Message, "1.1.0")
+		}
+	})
+
+	t.Run("equal versions clear condition", func(t *testing.T) {
+		addon := newTestAddonWithCatalogSourceImage()
+		setUpgradeAvailableCondition(addon, &v1_0_0, &v1_1_0)
+
+		// upgrade got installed
+		changed := setUpgradeAvailableCondition(addon, &v1_1_0, &v1_1_0)
+		assert.True(t, changed)
+
+		cond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.UpgradeAvailable)
+		if assert.NotNil(t, cond) {
+			assert.Equal(t, metav1.ConditionFalse, cond.Status)
+			assert.Equal(t, "AtLatestKnownVersion", cond.Reason)
+			assert.Equal(t, "Installed version 1.1.0 is the latest known version", cond.Message)
+		}
+	})
+
+	t.Run("older channel head is no upgrade", func(t *testing.T) {
+		addon := newTestAddonWithCatalogSourceImage()
+		setUpgradeAvailableCondition(addon, &v1_1_0, &v1_0_0)
+
+		assert.False(t, meta.IsStatusConditionTrue(addon.Status.Conditions, addonsv1alpha1.UpgradeAvailable))
+	})
+}
+
+func mockGetInstalledCSVVersion(c *testutil.Client, csvName, version string) {
+	c.On("Get", mock.Anything, client.ObjectKey{
+		Name:      csvName,
+		Namespace: "addon-1",
+	}, mock.IsType(&operatorsv1alpha1.ClusterServiceVersion{})).
+		Run(func(args mock.Arguments) {
+			csv := args.Get(2).(*operatorsv1alpha1.ClusterServiceVersion)
+			csv.Spec.Version = opversion.OperatorVersion{Version: semver.MustParse(version)}
+		}).
+		Return(nil)
+}
+
+func newTestAddonWithUpgradeChannel() *addonsv1alpha1.Addon {
+	addon := newTestAddonWithCatalogSourceImage()
+	addon.Spec.Install.OLMOwnNamespace.PackageName = "addon-1-package"
+	addon.Spec.Install.OLMOwnNamespace.Channel = "stable"
+	return addon
+}
+
+// Returns the status of a PackageManifest served from the given CatalogSource,
+// with the given head of the stable channel.
+func newTestPackageManifestStatus(catalogSource, stableHead, stableVersion string) map[string]interface{} {
+	return map[string]interface{}{
+		"catalogSource": catalogSource,
+		"channels": []interface{}{
+			map[string]interface{}{
+				"name":       "alpha",
+				"currentCSV": "addon-1.v2.0.0-alpha",
+				"currentCSVDesc": map[string]interface{}{
+					"version": "2.0.0-alpha",
+				},
+			},
+			map[string]interface{}{
+				"name":       "stable",
+				"currentCSV": stableHead,
+				"currentCSVDesc": map[string]interface{}{
+					"version": stableVersion,
+				},
+			},
+		},
+	}
+}
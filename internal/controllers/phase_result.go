@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+// phaseResult tells the Reconcile loop how to proceed after a phase.
+type phaseResult struct {
+	// signals that no further phases should run
+	stop bool
+	// signals that the Addon should be requeued immediately
+	requeue bool
+	// requeues the Addon after the given duration,
+	// the shortest duration of all phases wins
+	requeueAfter time.Duration
+	// terminal error, stops all further phases
+	err error
+}
+
+// The phase is done, continue with the next phase.
+func resultDone() phaseResult {
+	return phaseResult{}
+}
+
+// Continue with the next phase, but requeue the Addon after the given duration.
+// A duration of 0 does not requeue.
+func resultRequeueAfter(requeueAfter time.Duration) phaseResult {
+	return phaseResult{requeueAfter: requeueAfter}
+}
+
+// Stop reconciliation and wait for the next watch event.
+func resultStop() phaseResult {
+	return phaseResult{stop: true}
+}
+
+// Stop reconciliation and requeue the Addon immediately.
+func resultStopAndRequeue() phaseResult {
+	return phaseResult{stop: true, requeue: true}
+}
+
+// Stop reconciliation and requeue the Addon after the given duration.
+func resultStopAndRequeueAfter(requeueAfter time.Duration) phaseResult {
+	return phaseResult{stop: true, requeueAfter: requeueAfter}
+}
+
+// Stop reconciliation with a terminal error.
+func resultError(err error) phaseResult {
+	return phaseResult{stop: true, err: err}
+}
+
+// A single step of the Addon reconciliation.
+type phase func(ctx context.Context) phaseResult
+
+// Runs the given phases in order until one of them stops
+// and aggregates their results into a single ctrl.Result.
+func runPhases(ctx context.Context, phases ...phase) (ctrl.Result, error) {
+	var result ctrl.Result
+	for _, p := range phases {
+		// don't start new phases when the context is already done
+		if err := ctx.Err(); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		res := p(ctx)
+		if res.err != nil {
+			return ctrl.Result{}, res.err
+		}
+
+		if res.requeue {
+			result.Requeue = true
+		}
+		if res.requeueAfter > 0 &&
+			(result.RequeueAfter == 0 || res.requeueAfter < result.RequeueAfter) {
+			result.RequeueAfter = res.requeueAfter
+		}
+
+		if res.stop {
+			break
+		}
+	}
+	return result, nil
+}
+
+// Returns the configured reconcile timeout or the default.
+func (r *AddonReconciler) reconcileTimeout() time.Duration {
+	if r.ReconcileTimeout <= 0 {
+		return DefaultReconcileTimeout
+	}
+	return r.ReconcileTimeout
+}
+
+// Runs the given phases within the reconcile timeout.
+// When the timeout is exceeded, the remaining phases are abandoned,
+// a Timeout is reported and the Addon is requeued.
+func (r *AddonReconciler) runPhasesWithTimeout(
+	ctx context.Context, log logr.Logger,
+	addon *addonsv1alpha1.Addon, phases ...phase) (ctrl.Result, error) {
+	phaseCtx, cancel := context.WithTimeout(ctx, r.reconcileTimeout())
+	defer cancel()
+
+	result, err := runPhases(phaseCtx, phases...)
+	// phases that finished in time, even if just barely, are not abandoned
+	if err == nil || !errors.Is(phaseCtx.Err(), context.DeadlineExceeded) {
+		return result, err
+	}
+
+	log.Info("requeuing", "reason", "reconcile timeout")
+	// phaseCtx is expired, so we have to report with the parent context
+	if err := r.reportReconcileTimeout(ctx, addon); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to report reconcile timeout: %w", err)
+	}
+	return ctrl.Result{
+		RequeueAfter: defaultRetryAfterTime,
+	}, nil
+}
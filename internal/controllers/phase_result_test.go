@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+	"github.com/openshift/addon-operator/internal/testutil"
+)
+
+func TestRunPhases(t *testing.T) {
+	t.Run("requeue after propagates to final result", func(t *testing.T) {
+		var ran []int
+		result, err := runPhases(context.Background(),
+			func(ctx context.Context) phaseResult {
+				ran = append(ran, 0)
+				return resultRequeueAfter(time.Minute)
+			},
+			func(ctx context.Context) phaseResult {
+				ran = append(ran, 1)
+				return resultDone()
+			},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, []int{0, 1}, ran)
+		assert.Equal(t, ctrl.Result{RequeueAfter: time.Minute}, result)
+	})
+
+	t.Run("shortest requeue after wins", func(t *testing.T) {
+		result, err := runPhases(context.Background(),
+			func(ctx context.Context) phaseResult {
+				return resultRequeueAfter(time.Minute)
+			},
+			func(ctx context.Context) phaseResult {
+				return resultStopAndRequeueAfter(10 * time.Second)
+			},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: 10 * time.Second}, result)
+	})
+
+	t.Run("stop skips following phases", func(t *testing.T) {
+		result, err := runPhases(context.Background(),
+			func(ctx context.Context) phaseResult {
+				return resultStopAndRequeue()
+			},
+			func(ctx context.Context) phaseResult {
+				t.Fatal("phase after stop must not run")
+				return resultDone()
+			},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{Requeue: true}, result)
+	})
+
+	t.Run("terminal error stops pipeline", func(t *testing.T) {
+		testErr := errors.New("explosion")
+		result, err := runPhases(context.Background(),
+			func(ctx context.Context) phaseResult {
+				return resultRequeueAfter(time.Minute)
+			},
+			func(ctx context.Context) phaseResult {
+				return resultError(testErr)
+			},
+			func(ctx context.Context) phaseResult {
+				t.Fatal("phase after error must not run")
+				return resultDone()
+			},
+		)
+		assert.Equal(t, testErr, err)
+		assert.Equal(t, ctrl.Result{}, result)
+	})
+}
+
+func TestRunPhasesWithTimeout(t *testing.T) {
+	t.Run("stuck phase is abandoned", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client:           c,
+			Scheme:           newTestSchemeWithAddonsv1alpha1(),
+			ReconcileTimeout: 10 * time.Millisecond,
+		}
+
+		c.StatusMock.
+			On("Update", mock.Anything, testutil.IsAddonsv1alpha1AddonPtr, mock.Anything).
+			Return(nil)
+
+		addon := newTestAddonWithCatalogSourceImage()
+		result, err := r.runPhasesWithTimeout(
+			context.Background(), testutil.NewLogger(t), addon,
+			func(ctx context.Context) phaseResult {
+				// simulate a slow API call honoring the context
+				<-ctx.Done()
+				return resultError(ctx.Err())
+			},
+			func(ctx context.Context) phaseResult {
+				t.Fatal("phase after timeout must not run")
+				return resultDone()
+			},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: defaultRetryAfterTime}, result)
+
+		c.StatusMock.AssertCalled(t, "Update", mock.Anything, addon, mock.Anything)
+		availableCond := meta.FindStatusCondition(addon.Status.Conditions, addonsv1alpha1.Available)
+		if assert.NotNil(t, availableCond) {
+			assert.Equal(t, metav1.ConditionFalse, availableCond.Status)
+			assert.Equal(t, addonsv1alpha1.AddonReasonReconcileTimeout, availableCond.Reason)
+		}
+	})
+
+	t.Run("phases within timeout", func(t *testing.T) {
+		c := testutil.NewClient()
+		r := &AddonReconciler{
+			Client: c,
+			Scheme: newTestSchemeWithAddonsv1alpha1(),
+		}
+
+		result, err := r.runPhasesWithTimeout(
+			context.Background(), testutil.NewLogger(t), newTestAddonWithCatalogSourceImage(),
+			func(ctx context.Context) phaseResult {
+				_, hasDeadline := ctx.Deadline()
+				assert.True(t, hasDeadline)
+				return resultDone()
+			},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+		c.StatusMock.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,75 @@
+package testutil
+
+import (
+	"reflect"
+
+	"github.com/stretchr/testify/mock"
+	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ExpectGet returns a copy of the given object,
+// when an object of the same type and key is read.
+func (c *Client) ExpectGet(current client.Object) *mock.Call {
+	return c.On("Get", mock.Anything, client.ObjectKeyFromObject(current), mock.IsType(current)).
+		Run(func(args mock.Arguments) {
+			copyInto(args.Get(2).(client.Object), current)
+		}).
+		Return(nil)
+}
+
+// ExpectNotFound reports the object of the given type and key as missing.
+func (c *Client) ExpectNotFound(key client.ObjectKey, obj client.Object) *mock.Call {
+	return c.On("Get", mock.Anything, key, mock.IsType(obj)).
+		Return(k8sApiErrors.NewNotFound(schema.GroupResource{}, key.Name))
+}
+
+// ExpectCreate records a copy of the object created into the given object of the same type.
+func (c *Client) ExpectCreate(created client.Object) *mock.Call {
+	return c.On("Create", mock.Anything, mock.IsType(created), mock.Anything).
+		Run(func(args mock.Arguments) {
+			copyInto(created, args.Get(1).(client.Object))
+		}).
+		Return(nil)
+}
+
+// ExpectUpdate records a copy of the object updated into the given object of the same type.
+func (c *Client) ExpectUpdate(updated client.Object) *mock.Call {
+	return c.On("Update", mock.Anything, mock.IsType(updated), mock.Anything).
+		Run(func(args mock.Arguments) {
+			copyInto(updated, args.Get(1).(client.Object))
+		}).
+		Return(nil)
+}
+
+// ExpectList returns the given items, when a list of the given type is read.
+func (c *Client) ExpectList(list client.ObjectList, items ...runtime.Object) *mock.Call {
+	return c.On("List", mock.Anything, mock.IsType(list), mock.Anything).
+		Run(func(args mock.Arguments) {
+			if err := meta.SetList(args.Get(1).(client.ObjectList), items); err != nil {
+				panic(err)
+			}
+		}).
+		Return(nil)
+}
+
+// ExpectDelete allows deleting objects of the given type.
+func (c *Client) ExpectDelete(obj client.Object) *mock.Call {
+	return c.On("Delete", mock.Anything, mock.IsType(obj), mock.Anything).
+		Return(nil)
+}
+
+// ExpectStatusUpdate allows status updates of Addons.
+func (c *Client) ExpectStatusUpdate() *mock.Call {
+	return c.StatusMock.
+		On("Update", mock.Anything, IsAddonsv1alpha1AddonPtr, mock.Anything).
+		Return(nil)
+}
+
+// Deep copies src into dst, which have to be pointers to the same type.
+func copyInto(dst, src client.Object) {
+	reflect.ValueOf(dst).Elem().Set(reflect.ValueOf(src.DeepCopyObject()).Elem())
+}
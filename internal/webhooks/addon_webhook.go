@@ -0,0 +1,127 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+// Path the AddonWebhookHandler is served under.
+const AddonValidationPath = "/validate-addons"
+
+var _ admission.Handler = (*AddonWebhookHandler)(nil)
+
+// AddonWebhookHandler rejects Addons with an obviously invalid install spec
+// at admission time instead of letting them fail during reconciliation.
+type AddonWebhookHandler struct {
+	decoder *admission.Decoder
+}
+
+// InjectDecoder injects the decoder used to parse admission requests.
+func (h *AddonWebhookHandler) InjectDecoder(d *admission.Decoder) error {
+	h.decoder = d
+	return nil
+}
+
+func (h *AddonWebhookHandler) Handle(
+	ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create &&
+		req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	addon := &addonsv1alpha1.Addon{}
+	if err := h.decoder.Decode(req, addon); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if !addon.DeletionTimestamp.IsZero() {
+		// never block finalizer removal of Addons that are already being deleted
+		return admission.Allowed("")
+	}
+
+	if errs := validateAddonInstallSpec(
+		addon.Spec.Install, field.NewPath("spec", "install")); len(errs) > 0 {
+		return admission.Denied(errs.ToAggregate().Error())
+	}
+	return admission.Allowed("")
+}
+
+// Validates that the install spec references the config block of its type
+// and that all fields required to create the OLM objects are set.
+func validateAddonInstallSpec(
+	spec addonsv1alpha1.AddonInstallSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	switch spec.Type {
+	case addonsv1alpha1.OLMOwnNamespace:
+		if spec.OLMOwnNamespace == nil {
+			return append(allErrs, field.Required(
+				fldPath.Child("olmOwnNamespace"),
+				fmt.Sprintf("required when type is %s", spec.Type)))
+		}
+		allErrs = append(allErrs, validateAddonInstallOLMCommon(
+			spec.OLMOwnNamespace.AddonInstallOLMCommon,
+			fldPath.Child("olmOwnNamespace"))...)
+
+	case addonsv1alpha1.OLMAllNamespaces:
+		if spec.OLMAllNamespaces == nil {
+			return append(allErrs, field.Required(
+				fldPath.Child("olmAllNamespaces"),
+				fmt.Sprintf("required when type is %s", spec.Type)))
+		}
+		allErrs = append(allErrs, validateAddonInstallOLMCommon(
+			spec.OLMAllNamespaces.AddonInstallOLMCommon,
+			fldPath.Child("olmAllNamespaces"))...)
+
+	default:
+		allErrs = append(allErrs, field.NotSupported(
+			fldPath.Child("type"), spec.Type, []string{
+				string(addonsv1alpha1.OLMOwnNamespace),
+				string(addonsv1alpha1.OLMAllNamespaces),
+			}))
+	}
+	return allErrs
+}
+
+func validateAddonInstallOLMCommon(
+	common addonsv1alpha1.AddonInstallOLMCommon, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if len(common.Namespace) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("namespace"), ""))
+	} else {
+		for _, msg := range validation.IsDNS1123Label(common.Namespace) {
+			allErrs = append(allErrs, field.Invalid(
+				fldPath.Child("namespace"), common.Namespace, msg))
+		}
+	}
+
+	if len(common.CatalogSourceImage) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("catalogSourceImage"), ""))
+	} else if strings.ContainsAny(common.CatalogSourceImage, " \t\n") {
+		allErrs = append(allErrs, field.Invalid(
+			fldPath.Child("catalogSourceImage"), common.CatalogSourceImage,
+			"must not contain whitespace"))
+	}
+
+	if len(common.Channel) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("channel"), ""))
+	} else if strings.ContainsAny(common.Channel, " \t\n") {
+		allErrs = append(allErrs, field.Invalid(
+			fldPath.Child("channel"), common.Channel,
+			"must not contain whitespace"))
+	}
+
+	if len(common.PackageName) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("packageName"), ""))
+	}
+	return allErrs
+}
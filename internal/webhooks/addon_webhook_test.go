@@ -0,0 +1,214 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	addonsv1alpha1 "github.com/openshift/addon-operator/apis/addons/v1alpha1"
+)
+
+func TestAddonWebhookHandler(t *testing.T) {
+	h := newTestAddonWebhookHandler(t)
+
+	t.Run("admits valid Addon", func(t *testing.T) {
+		resp := h.Handle(context.Background(),
+			newTestAdmissionRequest(t, admissionv1.Create, newTestValidAddon()))
+		assert.True(t, resp.Allowed)
+	})
+
+	t.Run("denies invalid Addon", func(t *testing.T) {
+		addon := newTestValidAddon()
+		addon.Spec.Install.OLMOwnNamespace.CatalogSourceImage = ""
+
+		resp := h.Handle(context.Background(),
+			newTestAdmissionRequest(t, admissionv1.Update, addon))
+		assert.False(t, resp.Allowed)
+		assert.Contains(t, string(resp.Result.Reason),
+			"spec.install.olmOwnNamespace.catalogSourceImage: Required value")
+	})
+
+	t.Run("admits invalid Addon that is being deleted", func(t *testing.T) {
+		addon := newTestValidAddon()
+		addon.Spec.Install.OLMOwnNamespace.CatalogSourceImage = ""
+		now := metav1.Now()
+		addon.DeletionTimestamp = &now
+
+		resp := h.Handle(context.Background(),
+			newTestAdmissionRequest(t, admissionv1.Update, addon))
+		assert.True(t, resp.Allowed)
+	})
+
+	t.Run("ignores delete", func(t *testing.T) {
+		resp := h.Handle(context.Background(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Delete,
+			},
+		})
+		assert.True(t, resp.Allowed)
+	})
+}
+
+func TestValidateAddonInstallSpec(t *testing.T) {
+	tests := []struct {
+		name          string
+		modify        func(spec *addonsv1alpha1.AddonInstallSpec)
+		expectedField string
+	}{
+		{
+			name: "unknown type",
+			modify: func(spec *addonsv1alpha1.AddonInstallSpec) {
+				spec.Type = "Helm"
+			},
+			expectedField: "spec.install.type",
+		},
+		{
+			name: "missing type config",
+			modify: func(spec *addonsv1alpha1.AddonInstallSpec) {
+				spec.OLMOwnNamespace = nil
+			},
+			expectedField: "spec.install.olmOwnNamespace",
+		},
+		{
+			name: "missing namespace",
+			modify: func(spec *addonsv1alpha1.AddonInstallSpec) {
+				spec.OLMOwnNamespace.Namespace = ""
+			},
+			expectedField: "spec.install.olmOwnNamespace.namespace",
+		},
+		{
+			name: "invalid namespace",
+			modify: func(spec *addonsv1alpha1.AddonInstallSpec) {
+				spec.OLMOwnNamespace.Namespace = "Addon_1"
+			},
+			expectedField: "spec.install.olmOwnNamespace.namespace",
+		},
+		{
+			name: "missing catalog source image",
+			modify: func(spec *addonsv1alpha1.AddonInstallSpec) {
+				spec.OLMOwnNamespace.CatalogSourceImage = ""
+			},
+			expectedField: "spec.install.olmOwnNamespace.catalogSourceImage",
+		},
+		{
+			name: "invalid catalog source image",
+			modify: func(spec *addonsv1alpha1.AddonInstallSpec) {
+				spec.OLMOwnNamespace.CatalogSourceImage = "quay.io/osd-addons/test index"
+			},
+			expectedField: "spec.install.olmOwnNamespace.catalogSourceImage",
+		},
+		{
+			name: "missing channel",
+			modify: func(spec *addonsv1alpha1.AddonInstallSpec) {
+				spec.OLMOwnNamespace.Channel = ""
+			},
+			expectedField: "spec.install.olmOwnNamespace.channel",
+		},
+		{
+			name: "invalid channel",
+			modify: func(spec *addonsv1alpha1.AddonInstallSpec) {
+				spec.OLMOwnNamespace.Channel = "stable alpha"
+			},
+			expectedField: "spec.install.olmOwnNamespace.channel",
+		},
+		{
+			name: "missing package name",
+			modify: func(spec *addonsv1alpha1.AddonInstallSpec) {
+				spec.OLMOwnNamespace.PackageName = ""
+			},
+			expectedField: "spec.install.olmOwnNamespace.packageName",
+		},
+		{
+			name: "missing namespace for all namespaces",
+			modify: func(spec *addonsv1alpha1.AddonInstallSpec) {
+				spec.Type = addonsv1alpha1.OLMAllNamespaces
+				spec.OLMAllNamespaces = &addonsv1alpha1.AddonInstallOLMAllNamespaces{
+					AddonInstallOLMCommon: spec.OLMOwnNamespace.AddonInstallOLMCommon,
+				}
+				spec.OLMAllNamespaces.Namespace = ""
+			},
+			expectedField: "spec.install.olmAllNamespaces.namespace",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := newTestValidAddon().Spec.Install
+			test.modify(&spec)
+
+			errs := validateAddonInstallSpec(spec, field.NewPath("spec", "install"))
+			if assert.Len(t, errs, 1) {
+				assert.Equal(t, test.expectedField, errs[0].Field)
+			}
+		})
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		spec := newTestValidAddon().Spec.Install
+		// pinning by tag instead of digest is risky, but not invalid
+		spec.OLMOwnNamespace.CatalogSourceImage = "quay.io/osd-addons/test:latest"
+
+		errs := validateAddonInstallSpec(spec, field.NewPath("spec", "install"))
+		assert.Empty(t, errs)
+	})
+}
+
+func newTestAddonWebhookHandler(t *testing.T) *AddonWebhookHandler {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, addonsv1alpha1.AddToScheme(scheme))
+	decoder, err := admission.NewDecoder(scheme)
+	require.NoError(t, err)
+
+	h := &AddonWebhookHandler{}
+	require.NoError(t, h.InjectDecoder(decoder))
+	return h
+}
+
+func newTestAdmissionRequest(
+	t *testing.T, op admissionv1.Operation, addon *addonsv1alpha1.Addon) admission.Request {
+	t.Helper()
+
+	raw, err := json.Marshal(addon)
+	require.NoError(t, err)
+	return admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: op,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	}
+}
+
+func newTestValidAddon() *addonsv1alpha1.Addon {
+	return &addonsv1alpha1.Addon{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: addonsv1alpha1.GroupVersion.String(),
+			Kind:       "Addon",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "addon-1",
+		},
+		Spec: addonsv1alpha1.AddonSpec{
+			Install: addonsv1alpha1.AddonInstallSpec{
+				Type: addonsv1alpha1.OLMOwnNamespace,
+				OLMOwnNamespace: &addonsv1alpha1.AddonInstallOLMOwnNamespace{
+					AddonInstallOLMCommon: addonsv1alpha1.AddonInstallOLMCommon{
+						Namespace:          "addon-1",
+						CatalogSourceImage: "quay.io/osd-addons/test@sha256:04864220677b2ed6244f2e0d421166df908986700647595ffdb6fd9ca4e5098a",
+						Channel:            "stable",
+						PackageName:        "addon-1",
+					},
+				},
+			},
+		},
+	}
+}